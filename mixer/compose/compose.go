@@ -0,0 +1,208 @@
+// Package compose assembles a body and its bodyparts into a whole pet, the way
+// the renderer pins them, to preview and check combinations without a browser.
+package compose
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"tama/geom"
+	"tama/pet"
+	"tama/svgdoc"
+)
+
+// StrokeWidth is the width of the outlines drawn by the renderer, for paths
+// the sheet gives no width
+const StrokeWidth = 1.0
+
+// Composition is a pet ready to be drawn, from back to front: Out (arms and
+// legs), Body, then In (eyes and mouth). Every layer is drawn like the
+// renderer does, its fills first then its outlines.
+type Composition struct {
+	Body        []pet.SubPath `json:"body"`
+	In          []pet.SubPath `json:"in"`
+	Out         []pet.SubPath `json:"out"`
+	BoundingBox geom.Rect     `json:"boundingBox"`
+}
+
+// BodyPaths returns the sub-paths of a body, a body without them is drawn as
+// a single path filled with fill1
+func BodyPaths(body pet.Body) []pet.SubPath {
+	return withDefault(body.Paths, body.Path, pet.SlotFill1)
+}
+
+// PartPaths returns the sub-paths of a bodypart, a bodypart without them is
+// drawn as a single path filled with fill2
+func PartPaths(part pet.BodyPart) []pet.SubPath {
+	return withDefault(part.Paths, part.Path, pet.SlotFill2)
+}
+
+func withDefault(paths []pet.SubPath, d, fill string) []pet.SubPath {
+	if len(paths) > 0 || d == "" {
+		return paths
+	}
+	return []pet.SubPath{{Path: d, Fill: fill, Stroke: pet.SlotStroke, StrokeWidth: StrokeWidth, Opacity: 1, FillRule: "nonzero"}}
+}
+
+// Compose pins the bodypart of each anchor type on every anchor of the body,
+// rotated by the anchor angle, in front of the body or behind it as types
+// tells. Anchors without a bodypart are left empty.
+func Compose(body pet.Body, parts map[pet.BodypartType]pet.BodyPart, types pet.Registry) (Composition, error) {
+	c := Composition{Body: BodyPaths(body), In: make([]pet.SubPath, 0), Out: make([]pet.SubPath, 0), BoundingBox: body.BoundingBox}
+	for _, anchor := range body.Points {
+		part, ok := parts[anchor.Type]
+		if !ok {
+			continue
+		}
+		paths, rect, err := pinPart(part, anchor)
+		if err != nil {
+			return Composition{}, fmt.Errorf("%s %s-%d: %w", part.Type, part.Name, part.Frame, err)
+		}
+		if len(paths) == 0 {
+			continue
+		}
+		if types.Inside(anchor.Type) {
+			c.In = append(c.In, paths...)
+		} else {
+			c.Out = append(c.Out, paths...)
+		}
+		c.BoundingBox = c.BoundingBox.Union(rect)
+	}
+	return c, nil
+}
+
+// pinPart moves the bodypart onto the anchor, flipped first when the anchor
+// mirrors it, returning its sub-paths and their exact bounding box
+func pinPart(part pet.BodyPart, anchor pet.Point) ([]pet.SubPath, geom.Rect, error) {
+	m := geom.TranslateMatrix(anchor.X, anchor.Y).Multiply(geom.RotateMatrix(anchor.T))
+	if anchor.Mirror {
+		m = m.Multiply(geom.ScaleMatrix(-1, 1))
+	}
+	paths := make([]pet.SubPath, 0)
+	beziers := make([]geom.Bezier, 0)
+	for _, sub := range PartPaths(part) {
+		commands, err := geom.ParseD(sub.Path)
+		if err != nil {
+			return nil, geom.Rect{}, err
+		}
+		commands = m.TransformCommands(commands)
+		if len(commands) == 0 {
+			continue
+		}
+		beziers = append(beziers, geom.GetBeziersFromCommands(commands)...)
+		sub.Path = geom.CompileD(commands)
+		paths = append(paths, sub)
+	}
+	rect, ok := geom.BeziersBounds(beziers)
+	if !ok {
+		return nil, geom.Rect{}, nil
+	}
+	return paths, rect, nil
+}
+
+// OutlineWidth is the width the sub-path is outlined with, StrokeWidth when it
+// has none
+func OutlineWidth(sub pet.SubPath) float64 {
+	if sub.StrokeWidth > 0 {
+		return sub.StrokeWidth
+	}
+	return StrokeWidth
+}
+
+// ViewBox is the bounding box with room for the widest outline
+func (c Composition) ViewBox() geom.Rect {
+	widest := 0.0
+	for _, sub := range slices.Concat(c.Out, c.Body, c.In) {
+		if sub.Stroke != "" {
+			widest = max(widest, OutlineWidth(sub))
+		}
+	}
+	return c.BoundingBox.Inset(-widest / 2)
+}
+
+// Slots lists the palette slots the composition is drawn with
+func (c Composition) Slots() []string {
+	return pet.UsedSlots(c.Out, c.Body, c.In)
+}
+
+// SVG returns a standalone document drawing the composition with the palette
+// in its view box. Each layer is a group of fills followed by a group of
+// outlines, each drawn with its own width.
+func (c Composition) SVG(palette Palette) svgdoc.SVG {
+	box := c.ViewBox()
+	layers := []struct {
+		id    string
+		paths []pet.SubPath
+	}{
+		{"out", c.Out},
+		{"body", c.Body},
+		{"in", c.In},
+	}
+	groups := make([]svgdoc.Group, 0, len(layers))
+	for _, layer := range layers {
+		fills := svgdoc.Group{ID: layer.id + "-fill"}
+		strokes := svgdoc.Group{ID: layer.id + "-stroke"}
+		for i, sub := range layer.paths {
+			if color, ok := palette.Color(sub.Fill); ok {
+				fills.Paths = append(fills.Paths, svgdoc.Path{
+					ID:    layer.id + "-fill-" + strconv.Itoa(i),
+					D:     sub.Path,
+					Style: "fill:" + color + ";fill-rule:" + fillRule(sub) + ";stroke:none" + opacity(sub),
+				})
+			}
+			if color, ok := palette.Color(sub.Stroke); ok {
+				strokes.Paths = append(strokes.Paths, svgdoc.Path{
+					ID:    layer.id + "-stroke-" + strconv.Itoa(i),
+					D:     sub.Path,
+					Style: "fill:none;stroke:" + color + ";stroke-width:" + formatFloat(OutlineWidth(sub)) + opacity(sub),
+				})
+			}
+		}
+		if len(fills.Paths)+len(strokes.Paths) == 0 {
+			continue
+		}
+		groups = append(groups, svgdoc.Group{ID: layer.id, Groups: []svgdoc.Group{fills, strokes}})
+	}
+	return svgdoc.SVG{
+		Width:   formatFloat(box.Width()),
+		Height:  formatFloat(box.Height()),
+		ViewBox: strings.Join([]string{formatFloat(box.TopLeft.X), formatFloat(box.TopLeft.Y), formatFloat(box.Width()), formatFloat(box.Height())}, " "),
+		Xmlns:   "http://www.w3.org/2000/svg",
+		Groups:  groups,
+	}
+}
+
+func fillRule(sub pet.SubPath) string {
+	if sub.FillRule == "" {
+		return "nonzero"
+	}
+	return sub.FillRule
+}
+
+func opacity(sub pet.SubPath) string {
+	if sub.Opacity >= 1 {
+		return ""
+	}
+	return ";opacity:" + formatFloat(sub.Opacity)
+}
+
+// WriteSVG writes the standalone document of the composition
+func (c Composition) WriteSVG(w io.Writer, palette Palette) error {
+	data, err := xml.MarshalIndent(c.SVG(palette), "", "  ")
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
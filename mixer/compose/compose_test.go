@@ -0,0 +1,212 @@
+package compose
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+
+	"tama/geom"
+	"tama/pet"
+	"tama/svgdoc"
+)
+
+func testBody() pet.Body {
+	return pet.Body{
+		Path: "M 0 0 L 10 0 L 10 10 L 0 10 Z",
+		Points: []pet.Point{
+			{X: 3, Y: 3, Type: pet.BodypartType_Eye},
+			{X: 7, Y: 3, Type: pet.BodypartType_Eye},
+			{X: 5, Y: 10, T: 180, Type: pet.BodypartType_Leg1},
+			{X: 0, Y: 5, T: -90, Type: pet.BodypartType_Arm1},
+		},
+		Name:        "box",
+		BoundingBox: geom.Rect{TopLeft: geom.Point{X: 0, Y: 0}, BottomRight: geom.Point{X: 10, Y: 10}},
+	}
+}
+
+// layerBeziers parses every sub-path of a layer
+func layerBeziers(t *testing.T, paths []pet.SubPath) []geom.Bezier {
+	t.Helper()
+	beziers := make([]geom.Bezier, 0)
+	for _, sub := range paths {
+		commands, err := geom.ParseD(sub.Path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		beziers = append(beziers, geom.GetBeziersFromCommands(commands)...)
+	}
+	return beziers
+}
+
+func TestCompose(t *testing.T) {
+	parts := map[pet.BodypartType]pet.BodyPart{
+		pet.BodypartType_Eye:  {Path: "M -1 -1 L 1 -1 L 1 1 L -1 1 Z", Type: pet.BodypartType_Eye, Name: "dot"},
+		pet.BodypartType_Leg1: {Path: "M 0 0 L 0 -4", Type: pet.BodypartType_Leg1, Name: "stick"},
+	}
+	c, err := Compose(testBody(), parts, pet.DefaultRegistry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the leg points up, once turned by 180° it goes down from the anchor
+	beziers := layerBeziers(t, c.Out)
+	if len(beziers) != 1 || math.Abs(beziers[0].P3.X-5) > 1e-9 || math.Abs(beziers[0].P3.Y-14) > 1e-9 {
+		t.Errorf("leg is %v expected a line from (5, 10) to (5, 14)", beziers)
+	}
+
+	// both eyes get the same part, there is no arm
+	if rect, _ := geom.BeziersBounds(layerBeziers(t, c.In)); math.Abs(rect.TopLeft.X-2) > 1e-9 || math.Abs(rect.BottomRight.X-8) > 1e-9 {
+		t.Errorf("eyes span %v expected x from 2 to 8", rect)
+	}
+
+	want := geom.Rect{TopLeft: geom.Point{X: 0, Y: 0}, BottomRight: geom.Point{X: 10, Y: 14}}
+	if math.Abs(c.BoundingBox.BottomRight.Y-want.BottomRight.Y) > 1e-9 || c.BoundingBox.TopLeft != want.TopLeft || c.BoundingBox.BottomRight.X != want.BottomRight.X {
+		t.Errorf("got %v expected %v", c.BoundingBox, want)
+	}
+}
+
+func TestComposeBadPart(t *testing.T) {
+	parts := map[pet.BodypartType]pet.BodyPart{
+		pet.BodypartType_Arm1: {Path: "M 0 0 L x", Type: pet.BodypartType_Arm1, Name: "stick"},
+	}
+	if _, err := Compose(testBody(), parts, pet.DefaultRegistry); err == nil {
+		t.Errorf("expected an error for the bad arm path")
+	}
+}
+
+func TestCompositionWriteSVG(t *testing.T) {
+	c, err := Compose(testBody(), map[pet.BodypartType]pet.BodyPart{
+		pet.BodypartType_Leg1: {Path: "M 0 0 L 0 -4", Type: pet.BodypartType_Leg1, Name: "stick"},
+	}, pet.DefaultRegistry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := c.WriteSVG(&buf, PetPalette); err != nil {
+		t.Fatal(err)
+	}
+	svg, err := svgdoc.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if svg.ViewBox != "-0.5 -0.5 11 15" {
+		t.Errorf("got view box %q", svg.ViewBox)
+	}
+	// no eyes nor mouth, the in layer is left out
+	if len(svg.Groups) != 2 || svg.Groups[0].ID != "out" || svg.Groups[1].ID != "body" {
+		t.Fatalf("got layers %v expected out and body", svg.Groups)
+	}
+	// fills are drawn before the outlines
+	body := svg.Groups[1].Groups
+	if len(body) != 2 || len(body[0].Paths) != 1 || len(body[1].Paths) != 1 {
+		t.Fatalf("got body layer %v expected a fill and an outline", body)
+	}
+	if body[0].Paths[0].Style != "fill:#fff79c;fill-rule:nonzero;stroke:none" {
+		t.Errorf("got body fill style %q", body[0].Paths[0].Style)
+	}
+	if body[1].Paths[0].Style != "fill:none;stroke:#004c84;stroke-width:1" {
+		t.Errorf("got body outline style %q", body[1].Paths[0].Style)
+	}
+}
+
+func TestCompositionSubPaths(t *testing.T) {
+	body := testBody()
+	body.Paths = []pet.SubPath{
+		{Path: body.Path, Fill: pet.SlotFill1, Stroke: pet.SlotStroke, Opacity: 1},
+		{Path: "M 4 4 L 6 4 L 6 6 Z", Fill: pet.SlotAccent, Opacity: 0.5, FillRule: "evenodd"},
+	}
+	c, err := Compose(body, map[pet.BodypartType]pet.BodyPart{
+		pet.BodypartType_Eye: {Path: "M 0 0 L 1 0", Type: pet.BodypartType_Eye, Name: "dot"},
+	}, pet.DefaultRegistry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{pet.SlotStroke, pet.SlotFill1, pet.SlotFill2, pet.SlotAccent}
+	if slots := c.Slots(); !slices.Equal(slots, want) {
+		t.Errorf("got slots %v expected %v", slots, want)
+	}
+
+	svg := c.SVG(PetPalette)
+	fills := svg.Groups[0].Groups[0].Paths
+	if len(fills) != 2 || fills[1].Style != "fill:#004c84;fill-rule:evenodd;stroke:none;opacity:0.5" {
+		t.Errorf("got body fills %v", fills)
+	}
+	// the accent has no outline
+	if strokes := svg.Groups[0].Groups[1].Paths; len(strokes) != 1 {
+		t.Errorf("got body outlines %v expected one", strokes)
+	}
+}
+
+func TestCompositionStrokeWidths(t *testing.T) {
+	c := Composition{
+		Body: []pet.SubPath{
+			{Path: "M 0 0 L 10 0 L 10 10 Z", Fill: pet.SlotFill1, Stroke: pet.SlotStroke, StrokeWidth: 0.25, Opacity: 1},
+			{Path: "M 2 2 L 4 4", Stroke: pet.SlotStroke, StrokeWidth: 3, Opacity: 1},
+			{Path: "M 5 5 L 6 6", Stroke: pet.SlotStroke, Opacity: 1},
+		},
+		BoundingBox: geom.Rect{TopLeft: geom.Point{X: 0, Y: 0}, BottomRight: geom.Point{X: 10, Y: 10}},
+	}
+	// padded by half the widest outline
+	if box := c.ViewBox(); box.TopLeft.X != -1.5 || box.BottomRight.Y != 11.5 {
+		t.Errorf("got view box %v", box)
+	}
+	strokes := c.SVG(PetPalette).Groups[0].Groups[1].Paths
+	for i, want := range []string{"0.25", "3", "1"} {
+		if !strings.HasSuffix(strokes[i].Style, "stroke-width:"+want) {
+			t.Errorf("outline %d: got style %q expected a width of %s", i, strokes[i].Style, want)
+		}
+	}
+}
+
+func TestPaletteCheck(t *testing.T) {
+	slots := []string{pet.SlotStroke, pet.SlotFill1, pet.SlotAccent}
+	if err := PetPalette.Check(slots); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	palette := Palette{Stroke: "#000000", BodyFill: "#ffffff", PartsFill: "#ffffff"}
+	if err := palette.Check(slots); !errors.Is(err, ErrMissingSlot) {
+		t.Errorf("got %v expected %v", err, ErrMissingSlot)
+	}
+}
+
+func TestLoadManifest(t *testing.T) {
+	manifest, err := LoadManifest(strings.NewReader(`{
+		"slots": ["stroke", "fill1", "fill2"],
+		"colors": {"#000000": "stroke"},
+		"palettes": {"night": {"stroke": "#000000", "fill1": "#202040", "fill2": "#404080"}}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manifest.Palettes["night"].BodyFill != "#202040" {
+		t.Errorf("got palettes %v", manifest.Palettes)
+	}
+
+	_, err = LoadManifest(strings.NewReader(`{"palettes": {"night": {"stroke": "#000000", "fill1": "#202040"}}}`))
+	if !errors.Is(err, ErrMissingSlot) {
+		t.Errorf("got %v expected %v", err, ErrMissingSlot)
+	}
+}
+
+func TestComposeMirror(t *testing.T) {
+	body := testBody()
+	body.Points = []pet.Point{
+		{X: 3, Y: 3, Type: pet.BodypartType_Eye, Side: pet.SideLeft},
+		{X: 7, Y: 3, Type: pet.BodypartType_Eye, Side: pet.SideRight, Index: 1, Mirror: true},
+	}
+	// the eye looks right, the mirrored one looks left
+	parts := map[pet.BodypartType]pet.BodyPart{
+		pet.BodypartType_Eye: {Path: "M 0 0 L 2 0", Type: pet.BodypartType_Eye, Name: "glance"},
+	}
+	c, err := Compose(body, parts, pet.DefaultRegistry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	beziers := layerBeziers(t, c.In)
+	if len(beziers) != 2 || math.Abs(beziers[0].P3.X-5) > 1e-9 || math.Abs(beziers[1].P3.X-5) > 1e-9 {
+		t.Errorf("eyes are %v expected both to end at x = 5", beziers)
+	}
+}
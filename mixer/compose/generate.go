@@ -0,0 +1,74 @@
+package compose
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"tama/pet"
+)
+
+// Combination is a body frame with a single bodypart frame pinned on it
+type Combination struct {
+	Body pet.Body
+	Part pet.BodyPart
+}
+
+// Name labels the combination as described in the README:
+// bodyshape_bodyframe@bodypart_type=bodypartname-bodypartsubname-bodypartframe.
+// The subname is part of the bodypart name when the sheet gives one
+// ("round-big-0"), otherwise it is left out with its dash.
+func (c Combination) Name() string {
+	return c.Body.Name + "_" + strconv.Itoa(c.Body.Frame) + "@" +
+		string(c.Part.Type) + "=" + c.Part.Name + "-" + strconv.Itoa(c.Part.Frame)
+}
+
+// Compose pins the bodypart of the combination on the body
+func (c Combination) Compose(types pet.Registry) (Composition, error) {
+	return Compose(c.Body, map[pet.BodypartType]pet.BodyPart{c.Part.Type: c.Part}, types)
+}
+
+// Filter restricts the combinations to some bodies, bodypart types and
+// bodypart names, an empty list allows everything
+type Filter struct {
+	Bodies []string
+	Types  []pet.BodypartType
+	Parts  []string
+}
+
+func (f Filter) allows(body pet.Body, part pet.BodyPart) bool {
+	return (len(f.Bodies) == 0 || slices.Contains(f.Bodies, body.Name)) &&
+		(len(f.Types) == 0 || slices.Contains(f.Types, part.Type)) &&
+		(len(f.Parts) == 0 || slices.Contains(f.Parts, part.Name))
+}
+
+// Combinations returns every body frame paired with every bodypart frame it
+// has an anchor for, ordered by body then bodypart
+func Combinations(bodies []pet.Body, bodyparts []pet.BodyPart, filter Filter) []Combination {
+	bodies = slices.Clone(bodies)
+	slices.SortStableFunc(bodies, func(a, b pet.Body) int {
+		return cmp.Or(strings.Compare(a.Name, b.Name), a.Frame-b.Frame)
+	})
+	bodyparts = slices.Clone(bodyparts)
+	slices.SortStableFunc(bodyparts, func(a, b pet.BodyPart) int {
+		return cmp.Or(strings.Compare(string(a.Type), string(b.Type)), strings.Compare(a.Name, b.Name), a.Frame-b.Frame)
+	})
+
+	results := make([]Combination, 0)
+	for _, body := range bodies {
+		for _, part := range bodyparts {
+			if !filter.allows(body, part) || !hasAnchor(body, part.Type) {
+				continue
+			}
+			results = append(results, Combination{Body: body, Part: part})
+		}
+	}
+	return results
+}
+
+func hasAnchor(body pet.Body, t pet.BodypartType) bool {
+	return slices.ContainsFunc(body.Points, func(p pet.Point) bool {
+		return p.Type == t
+	})
+}
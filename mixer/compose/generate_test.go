@@ -0,0 +1,48 @@
+package compose
+
+import (
+	"testing"
+
+	"tama/pet"
+)
+
+func TestCombinations(t *testing.T) {
+	bodies := []pet.Body{
+		{Name: "mush", Frame: 1, Points: []pet.Point{{Type: pet.BodypartType_Eye}}},
+		{Name: "ball", Frame: 0, Points: []pet.Point{{Type: pet.BodypartType_Eye}, {Type: pet.BodypartType_Arm1}}},
+		{Name: "mush", Frame: 0, Points: []pet.Point{{Type: pet.BodypartType_Eye}}},
+	}
+	bodyparts := []pet.BodyPart{
+		{Name: "stick", Type: pet.BodypartType_Arm1, Frame: 0},
+		{Name: "round-big", Type: pet.BodypartType_Eye, Frame: 1},
+		{Name: "round-big", Type: pet.BodypartType_Eye, Frame: 0},
+	}
+
+	var names []string
+	for _, c := range Combinations(bodies, bodyparts, Filter{}) {
+		names = append(names, c.Name())
+	}
+	// mush has no arm anchor
+	want := []string{
+		"ball_0@arm1=stick-0",
+		"ball_0@eye=round-big-0",
+		"ball_0@eye=round-big-1",
+		"mush_0@eye=round-big-0",
+		"mush_0@eye=round-big-1",
+		"mush_1@eye=round-big-0",
+		"mush_1@eye=round-big-1",
+	}
+	if len(names) != len(want) {
+		t.Fatalf("got %v expected %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Errorf("combination %d: got %s expected %s", i, names[i], want[i])
+		}
+	}
+
+	filtered := Combinations(bodies, bodyparts, Filter{Bodies: []string{"ball"}, Types: []pet.BodypartType{pet.BodypartType_Eye}})
+	if len(filtered) != 2 || filtered[0].Name() != "ball_0@eye=round-big-0" {
+		t.Errorf("got %v expected the two ball eyes", filtered)
+	}
+}
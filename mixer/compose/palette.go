@@ -0,0 +1,124 @@
+package compose
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+
+	"tama/pet"
+	"tama/svgdoc"
+)
+
+// Palette holds the color of every palette slot, see COLOR_PALETTE in the
+// renderer
+type Palette struct {
+	Stroke    string `json:"stroke"`
+	BodyFill  string `json:"fill1"`
+	PartsFill string `json:"fill2"`
+	Accent    string `json:"accent,omitempty"`
+	Highlight string `json:"highlight,omitempty"`
+}
+
+// PetPalette is COLOR_PALETTE.pet, accents are drawn with the outline color
+var PetPalette = Palette{Stroke: "#004c84", BodyFill: "#fff79c", PartsFill: "#4192cd", Accent: "#004c84", Highlight: "#ffffff"}
+
+var ErrMissingSlot = errors.New("palette has no color for slot")
+
+// Color returns the color of a slot, false when the palette leaves it empty
+func (p Palette) Color(slot string) (string, bool) {
+	var color string
+	switch slot {
+	case pet.SlotStroke:
+		color = p.Stroke
+	case pet.SlotFill1:
+		color = p.BodyFill
+	case pet.SlotFill2:
+		color = p.PartsFill
+	case pet.SlotAccent:
+		color = p.Accent
+	case pet.SlotHighlight:
+		color = p.Highlight
+	}
+	return color, color != ""
+}
+
+// Check makes sure the palette has a color for every slot
+func (p Palette) Check(slots []string) error {
+	for _, slot := range slots {
+		if _, ok := p.Color(slot); !ok {
+			return fmt.Errorf("%w %s", ErrMissingSlot, slot)
+		}
+	}
+	return nil
+}
+
+// Manifest describes the palettes of a pet: the slot of every color drawn in
+// the sheets, the slots the extracted frames use, and named palettes giving
+// each slot a color
+type Manifest struct {
+	Version  int                `json:"version"`
+	Slots    []string           `json:"slots"`
+	Colors   map[string]string  `json:"colors"`
+	Used     []string           `json:"used,omitempty"`
+	Palettes map[string]Palette `json:"palettes"`
+}
+
+// UsedSlots lists the palette slots the bodies and bodyparts are drawn with,
+// in the order of pet.Slots
+func UsedSlots(bodies []pet.Body, bodyparts []pet.BodyPart) []string {
+	paths := make([][]pet.SubPath, 0, len(bodies)+len(bodyparts))
+	for _, body := range bodies {
+		paths = append(paths, BodyPaths(body))
+	}
+	for _, part := range bodyparts {
+		paths = append(paths, PartPaths(part))
+	}
+	return pet.UsedSlots(paths...)
+}
+
+// Recolor draws the same composition with each palette, every palette must
+// have a color for the slots the composition uses
+func Recolor(c Composition, palettes map[string]Palette) (map[string]svgdoc.SVG, error) {
+	svgs := make(map[string]svgdoc.SVG, len(palettes))
+	for name, palette := range palettes {
+		if err := palette.Check(c.Slots()); err != nil {
+			return nil, fmt.Errorf("palette %q: %w", name, err)
+		}
+		svgs[name] = c.SVG(palette)
+	}
+	return svgs, nil
+}
+
+// NewManifest describes the slots of the sheet colors, the slots in use and
+// the palettes, the renderer one when there is none
+func NewManifest(bodies []pet.Body, bodyparts []pet.BodyPart, colors pet.ColorSlots, palettes map[string]Palette) Manifest {
+	if len(palettes) == 0 {
+		palettes = map[string]Palette{"pet": PetPalette}
+	}
+	return Manifest{
+		Slots:    slices.Clone(pet.Slots),
+		Colors:   maps.Clone(colors),
+		Used:     UsedSlots(bodies, bodyparts),
+		Palettes: maps.Clone(palettes),
+	}
+}
+
+// LoadManifest reads a manifest written by hand or by the mixer, only its
+// colors and palettes are used
+func LoadManifest(r io.Reader) (Manifest, error) {
+	var manifest Manifest
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&manifest); err != nil {
+		return Manifest{}, err
+	}
+	for name, palette := range manifest.Palettes {
+		if err := palette.Check([]string{pet.SlotStroke, pet.SlotFill1, pet.SlotFill2}); err != nil {
+			return Manifest{}, fmt.Errorf("palette %q: %w", name, err)
+		}
+	}
+	return manifest, nil
+}
@@ -0,0 +1,128 @@
+// Package export writes extracted bodies and bodyparts to disk.
+package export
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+
+	"tama/compose"
+	"tama/pet"
+)
+
+// FormatVersion is stamped in every JSON file the mixer writes, it is bumped
+// whenever a loader of the previous version would misread them
+const FormatVersion = 1
+
+// BodyParts is the content of a bodyparts file, the frames of a family
+type BodyParts struct {
+	Version int            `json:"version"`
+	Frames  []pet.BodyPart `json:"frames"`
+}
+
+// Bodies is the content of a bodies file, the frames of a body
+type Bodies struct {
+	Version int        `json:"version"`
+	Frames  []pet.Body `json:"frames"`
+}
+
+// Types is the content of the types file
+type Types struct {
+	Version int            `json:"version"`
+	Types   []pet.TypeInfo `json:"types"`
+}
+
+func SaveBodyPartsToJSON(prefix string, bodyparts []pet.BodyPart) error {
+	if err := os.MkdirAll(prefix, 0755); err != nil {
+		return err
+	}
+	filename := path.Base(BodyPartsFile(bodyparts[0].Type, bodyparts[0].Name))
+	file, err := os.Create(filepath.Join(prefix, filename))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(BodyParts{Version: FormatVersion, Frames: withEmptyBodyParts(bodyparts)})
+}
+
+// withEmptyBodyParts copies the bodyparts with empty slices instead of nil
+// ones, the schema wants arrays and not null
+func withEmptyBodyParts(bodyparts []pet.BodyPart) []pet.BodyPart {
+	bodyparts = slices.Clone(bodyparts)
+	for i := range bodyparts {
+		if bodyparts[i].Paths == nil {
+			bodyparts[i].Paths = make([]pet.SubPath, 0)
+		}
+	}
+	return bodyparts
+}
+
+func SaveBodiesToJSON(prefix string, bodies []pet.Body) error {
+	if err := os.MkdirAll(prefix, 0755); err != nil {
+		return err
+	}
+	filename := path.Base(BodiesFile(bodies[0].Name))
+	file, err := os.Create(filepath.Join(prefix, filename))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(Bodies{Version: FormatVersion, Frames: withEmptyBodies(bodies)})
+}
+
+// withEmptyBodies is withEmptyBodyParts for bodies
+func withEmptyBodies(bodies []pet.Body) []pet.Body {
+	bodies = slices.Clone(bodies)
+	for i := range bodies {
+		if bodies[i].Paths == nil {
+			bodies[i].Paths = make([]pet.SubPath, 0)
+		}
+		if bodies[i].Points == nil {
+			bodies[i].Points = make([]pet.Point, 0)
+		}
+	}
+	return bodies
+}
+
+// SaveTypesToJSON writes the bodypart types as prefix/types.json, in the
+// format read by pet.LoadTypes
+func SaveTypesToJSON(prefix string, types []pet.TypeInfo) error {
+	if err := os.MkdirAll(prefix, 0755); err != nil {
+		return err
+	}
+	file, err := os.Create(filepath.Join(prefix, "types.json"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(Types{Version: FormatVersion, Types: types})
+}
+
+// SavePaletteManifest writes the color slots and palettes as
+// prefix/palette.json, in the format read by compose.LoadManifest
+func SavePaletteManifest(prefix string, manifest compose.Manifest) error {
+	manifest.Version = FormatVersion
+	if err := os.MkdirAll(prefix, 0755); err != nil {
+		return err
+	}
+	file, err := os.Create(filepath.Join(prefix, "palette.json"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(manifest)
+}
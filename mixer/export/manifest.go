@@ -0,0 +1,119 @@
+package export
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+
+	"tama/pet"
+)
+
+// Manifest lists every exported file, loaders find the assets from it
+// instead of globbing the output directories. Paths are relative to the
+// manifest and use forward slashes.
+type Manifest struct {
+	Version int            `json:"version"`
+	Types   []pet.TypeInfo `json:"types"`
+	Bodies  []BodyEntry    `json:"bodies"`
+	Parts   []PartEntry    `json:"parts"`
+	Palette string         `json:"palette,omitempty"`
+}
+
+// BodyEntry describes the frames of a body and the bodypart types it anchors
+type BodyEntry struct {
+	Name    string             `json:"name"`
+	File    string             `json:"file"`
+	Frames  []int              `json:"frames"`
+	Anchors []pet.BodypartType `json:"anchors"`
+}
+
+// PartEntry describes a bodypart family, the frames of a name and type. Pair
+// is the ID of the family drawn on the other side (arm1-wing for arm2-wing),
+// empty when the type has no pair or the other side isn't drawn.
+type PartEntry struct {
+	ID     string           `json:"id"`
+	Type   pet.BodypartType `json:"type"`
+	Name   string           `json:"name"`
+	File   string           `json:"file"`
+	Frames []int            `json:"frames"`
+	Pair   string           `json:"pair,omitempty"`
+}
+
+// BodiesFile is the path of the frames of a body, relative to the output root
+func BodiesFile(name string) string {
+	return path.Join("bodies", name+".json")
+}
+
+// BodyPartsFile is the path of the frames of a bodypart family, relative to
+// the output root
+func BodyPartsFile(t pet.BodypartType, name string) string {
+	return path.Join("bodyparts", partID(t, name)+".json")
+}
+
+func partID(t pet.BodypartType, name string) string {
+	return string(t) + "-" + name
+}
+
+// NewManifest describes the bodies and bodyparts as written by
+// SaveBodiesToJSON and SaveBodyPartsToJSON, in the order of the bodypart
+// types then by name
+func NewManifest(bodies []pet.Body, bodyparts []pet.BodyPart, types pet.Registry) Manifest {
+	manifest := Manifest{
+		Version: FormatVersion,
+		Types:   types.Types(),
+		Bodies:  make([]BodyEntry, 0),
+		Parts:   make([]PartEntry, 0),
+	}
+	for _, group := range pet.GroupBodies(slices.Clone(bodies)) {
+		entry := BodyEntry{Name: group[0].Name, File: BodiesFile(group[0].Name), Frames: make([]int, 0, len(group)), Anchors: make([]pet.BodypartType, 0)}
+		for _, body := range group {
+			entry.Frames = append(entry.Frames, body.Frame)
+			for _, anchor := range body.Points {
+				if !slices.Contains(entry.Anchors, anchor.Type) {
+					entry.Anchors = append(entry.Anchors, anchor.Type)
+				}
+			}
+		}
+		slices.SortStableFunc(entry.Anchors, types.Compare)
+		manifest.Bodies = append(manifest.Bodies, entry)
+	}
+
+	families := make(map[string]bool)
+	for _, group := range pet.GroupBodyParts(slices.Clone(bodyparts)) {
+		families[partID(group[0].Type, group[0].Name)] = true
+	}
+	for _, group := range pet.GroupBodyParts(slices.Clone(bodyparts)) {
+		t, name := group[0].Type, group[0].Name
+		entry := PartEntry{ID: partID(t, name), Type: t, Name: name, File: BodyPartsFile(t, name), Frames: make([]int, 0, len(group))}
+		for _, part := range group {
+			entry.Frames = append(entry.Frames, part.Frame)
+		}
+		if other, ok := types.Pair(t); ok && families[partID(other, name)] {
+			entry.Pair = partID(other, name)
+		}
+		manifest.Parts = append(manifest.Parts, entry)
+	}
+	slices.SortStableFunc(manifest.Parts, func(a, b PartEntry) int {
+		return types.Compare(a.Type, b.Type)
+	})
+	return manifest
+}
+
+// SaveManifest writes the manifest as prefix/manifest.json
+func SaveManifest(prefix string, manifest Manifest) error {
+	manifest.Version = FormatVersion
+	if err := os.MkdirAll(prefix, 0755); err != nil {
+		return err
+	}
+	file, err := os.Create(filepath.Join(prefix, "manifest.json"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(manifest)
+}
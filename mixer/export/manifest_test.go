@@ -0,0 +1,134 @@
+package export
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"tama/pet"
+	"tama/schema"
+)
+
+func TestNewManifest(t *testing.T) {
+	bodies := []pet.Body{
+		{Name: "ball", Frame: 1, Points: []pet.Point{{Type: pet.BodypartType_Arm2}, {Type: pet.BodypartType_Eye}}},
+		{Name: "ball", Frame: 0, Points: []pet.Point{{Type: pet.BodypartType_Arm1}, {Type: pet.BodypartType_Eye}}},
+	}
+	bodyparts := []pet.BodyPart{
+		{Type: pet.BodypartType_Arm2, Name: "wing", Frame: 0},
+		{Type: pet.BodypartType_Arm1, Name: "wing", Frame: 1},
+		{Type: pet.BodypartType_Arm1, Name: "wing", Frame: 0},
+		{Type: pet.BodypartType_Arm1, Name: "stick", Frame: 0},
+		{Type: pet.BodypartType_Eye, Name: "round", Frame: 0},
+	}
+	manifest := NewManifest(bodies, bodyparts, pet.DefaultRegistry)
+	if manifest.Version != FormatVersion || len(manifest.Types) != len(pet.DefaultRegistry.Types()) {
+		t.Errorf("got version %d and %d types", manifest.Version, len(manifest.Types))
+	}
+
+	if len(manifest.Bodies) != 1 {
+		t.Fatalf("got bodies %v expected ball", manifest.Bodies)
+	}
+	ball := manifest.Bodies[0]
+	wantAnchors := []pet.BodypartType{pet.BodypartType_Eye, pet.BodypartType_Arm1, pet.BodypartType_Arm2}
+	if ball.File != "bodies/ball.json" || !slices.Equal(ball.Frames, []int{0, 1}) || !slices.Equal(ball.Anchors, wantAnchors) {
+		t.Errorf("got body %+v", ball)
+	}
+
+	want := []PartEntry{
+		{ID: "eye-round", Type: pet.BodypartType_Eye, Name: "round", File: "bodyparts/eye-round.json", Frames: []int{0}},
+		{ID: "arm1-stick", Type: pet.BodypartType_Arm1, Name: "stick", File: "bodyparts/arm1-stick.json", Frames: []int{0}},
+		{ID: "arm1-wing", Type: pet.BodypartType_Arm1, Name: "wing", File: "bodyparts/arm1-wing.json", Frames: []int{0, 1}, Pair: "arm2-wing"},
+		{ID: "arm2-wing", Type: pet.BodypartType_Arm2, Name: "wing", File: "bodyparts/arm2-wing.json", Frames: []int{0}, Pair: "arm1-wing"},
+	}
+	if !slices.EqualFunc(manifest.Parts, want, func(a, b PartEntry) bool {
+		return a.ID == b.ID && a.Type == b.Type && a.Name == b.Name && a.File == b.File && slices.Equal(a.Frames, b.Frames) && a.Pair == b.Pair
+	}) {
+		t.Errorf("got parts %+v expected %+v", manifest.Parts, want)
+	}
+}
+
+func TestSaveManifest(t *testing.T) {
+	dir := t.TempDir()
+	bodyparts := []pet.BodyPart{{Type: pet.BodypartType_Eye, Name: "round", Frame: 0}}
+	if err := SaveBodyPartsToJSON(filepath.Join(dir, "bodyparts"), bodyparts); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveManifest(dir, NewManifest(nil, bodyparts, pet.DefaultRegistry)); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "manifest.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatal(err)
+	}
+	// every listed file exists
+	for _, part := range manifest.Parts {
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(part.File))); err != nil {
+			t.Errorf("%s: %v", part.ID, err)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	dir := t.TempDir()
+	bodies := []pet.Body{{Name: "ball", Frame: 0, Points: []pet.Point{{Type: pet.BodypartType_Eye}}}}
+	bodyparts := []pet.BodyPart{{Type: pet.BodypartType_Eye, Name: "round", Frame: 0}}
+	if err := SaveBodiesToJSON(filepath.Join(dir, "bodies"), bodies); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveBodyPartsToJSON(filepath.Join(dir, "bodyparts"), bodyparts); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveTypesToJSON(dir, pet.DefaultRegistry.Types()); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveManifest(dir, NewManifest(bodies, bodyparts, pet.DefaultRegistry)); err != nil {
+		t.Fatal(err)
+	}
+	if errs := Verify(dir); len(errs) != 0 {
+		t.Fatalf("got %v expected a valid directory", errs)
+	}
+
+	// a body written by a former version, and one the manifest doesn't know
+	if err := os.WriteFile(filepath.Join(dir, "bodies", "ball.json"), []byte(`[{"name": "ball"}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveBodiesToJSON(filepath.Join(dir, "bodies"), []pet.Body{{Name: "mush"}}); err != nil {
+		t.Fatal(err)
+	}
+	errs := Verify(dir)
+	if len(errs) != 2 || !errors.Is(errs[0], schema.ErrType) || !errors.Is(errs[1], ErrNotListed) {
+		t.Errorf("got %v expected a wrong type and an unlisted file", errs)
+	}
+}
+
+func TestSaveUnderFile(t *testing.T) {
+	// the output root can't be created under a file
+	file := filepath.Join(t.TempDir(), "out")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	prefix := filepath.Join(file, "bodies")
+	saves := map[string]func() error{
+		"bodies": func() error { return SaveBodiesToJSON(prefix, []pet.Body{{Name: "ball"}}) },
+		"bodyparts": func() error {
+			return SaveBodyPartsToJSON(prefix, []pet.BodyPart{{Type: pet.BodypartType_Eye, Name: "round"}})
+		},
+		"types":    func() error { return SaveTypesToJSON(prefix, pet.DefaultRegistry.Types()) },
+		"manifest": func() error { return SaveManifest(prefix, Manifest{}) },
+		"schema":   func() error { return SaveSchema(prefix) },
+	}
+	for name, save := range saves {
+		if err := save(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package export
+
+import (
+	"encoding/json"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+
+	"tama/sprite"
+)
+
+// SaveSpriteSheet writes the sheet as prefix/name.png and its atlas, pointing
+// to it, as prefix/name.json
+func SaveSpriteSheet(prefix, name string, sheet image.Image, atlas sprite.Atlas) error {
+	if err := os.MkdirAll(prefix, 0755); err != nil {
+		return err
+	}
+	imageFile, err := os.Create(filepath.Join(prefix, name+".png"))
+	if err != nil {
+		return err
+	}
+	defer imageFile.Close()
+	if err := png.Encode(imageFile, sheet); err != nil {
+		return err
+	}
+
+	atlas.Version = FormatVersion
+	atlas.Image = name + ".png"
+	file, err := os.Create(filepath.Join(prefix, name+".json"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(atlas)
+}
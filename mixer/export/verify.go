@@ -0,0 +1,161 @@
+package export
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"slices"
+
+	"tama/compose"
+	"tama/schema"
+	"tama/sprite"
+)
+
+var (
+	ErrNotListed = errors.New("file is not listed in the manifest")
+	ErrFrames    = errors.New("frames differ from the manifest")
+)
+
+// Schema describes every JSON file the mixer writes, each under its own
+// definition: bodies, bodyparts, types, palette, manifest and atlas. Their
+// version must be FormatVersion.
+func Schema() (*schema.Schema, error) {
+	roots := []schema.Root{
+		{Name: "bodies", Type: reflect.TypeFor[Bodies]()},
+		{Name: "bodyparts", Type: reflect.TypeFor[BodyParts]()},
+		{Name: "types", Type: reflect.TypeFor[Types]()},
+		{Name: "palette", Type: reflect.TypeFor[compose.Manifest]()},
+		{Name: "manifest", Type: reflect.TypeFor[Manifest]()},
+		{Name: "atlas", Type: reflect.TypeFor[sprite.Atlas]()},
+	}
+	doc, err := schema.Document("", roots...)
+	if err != nil {
+		return nil, err
+	}
+	for _, root := range roots {
+		doc.Defs[root.Name].Properties["version"] = &schema.Schema{Type: "integer", Const: FormatVersion}
+	}
+	return doc, nil
+}
+
+// SaveSchema writes the schema of the files as prefix/schema.json
+func SaveSchema(prefix string) error {
+	doc, err := Schema()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(prefix, 0755); err != nil {
+		return err
+	}
+	file, err := os.Create(filepath.Join(prefix, "schema.json"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(doc)
+}
+
+// Verify checks an output directory against the schema: the manifest, every
+// file it lists, and any other JSON file of the directory, taken for a sprite
+// atlas. Bodies and bodyparts files must be listed with their frames. Every
+// problem is returned, with the path of its file.
+func Verify(dir string) []error {
+	doc, err := Schema()
+	if err != nil {
+		return []error{err}
+	}
+	v := verifier{doc: doc, dir: dir, seen: make(map[string]bool)}
+
+	var manifest Manifest
+	if !v.check("manifest.json", "manifest", &manifest) {
+		return v.errs
+	}
+	v.check("types.json", "types", nil)
+	if manifest.Palette != "" {
+		v.check(manifest.Palette, "palette", nil)
+	}
+	for _, entry := range manifest.Bodies {
+		var bodies Bodies
+		if v.check(entry.File, "bodies", &bodies) {
+			frames := make([]int, 0, len(bodies.Frames))
+			for _, body := range bodies.Frames {
+				frames = append(frames, body.Frame)
+			}
+			v.compareFrames(entry.File, entry.Frames, frames)
+		}
+	}
+	for _, entry := range manifest.Parts {
+		var bodyparts BodyParts
+		if v.check(entry.File, "bodyparts", &bodyparts) {
+			frames := make([]int, 0, len(bodyparts.Frames))
+			for _, part := range bodyparts.Frames {
+				frames = append(frames, part.Frame)
+			}
+			v.compareFrames(entry.File, entry.Frames, frames)
+		}
+	}
+
+	for _, sub := range []string{"bodies", "bodyparts"} {
+		names, _ := filepath.Glob(filepath.Join(dir, sub, "*.json"))
+		for _, name := range names {
+			if file := path.Join(sub, filepath.Base(name)); !v.seen[file] {
+				v.errs = append(v.errs, fmt.Errorf("%s: %w", file, ErrNotListed))
+			}
+		}
+	}
+	names, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	for _, name := range names {
+		if file := filepath.Base(name); !v.seen[file] && file != "schema.json" {
+			v.check(file, "atlas", nil)
+		}
+	}
+	return v.errs
+}
+
+type verifier struct {
+	doc  *schema.Schema
+	dir  string
+	seen map[string]bool
+	errs []error
+}
+
+// check validates the file against the definition, and decodes it into
+// into when it is valid
+func (v *verifier) check(file, def string, into any) bool {
+	v.seen[file] = true
+	data, err := os.ReadFile(filepath.Join(v.dir, filepath.FromSlash(file)))
+	if err != nil {
+		v.errs = append(v.errs, err)
+		return false
+	}
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		v.errs = append(v.errs, fmt.Errorf("%s: %w", file, err))
+		return false
+	}
+	errs := v.doc.Validate(def, value)
+	for _, err := range errs {
+		v.errs = append(v.errs, fmt.Errorf("%s: %w", file, err))
+	}
+	if len(errs) > 0 || into == nil {
+		return len(errs) == 0
+	}
+	if err := json.Unmarshal(data, into); err != nil {
+		v.errs = append(v.errs, fmt.Errorf("%s: %w", file, err))
+		return false
+	}
+	return true
+}
+
+func (v *verifier) compareFrames(file string, listed, frames []int) {
+	if !slices.Equal(listed, frames) {
+		v.errs = append(v.errs, fmt.Errorf("%s: %w: %v listed, %v found", file, ErrFrames, listed, frames))
+	}
+}
@@ -0,0 +1,100 @@
+package extract
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"tama/svgdoc"
+)
+
+var (
+	ErrBadLabel = errors.New(`label does not match "name-frame"`)
+	ErrNoAnchor = errors.New("no root anchor, a bodypart needs an ellipse or a circle")
+	ErrNoPath   = errors.New("no path")
+	// ErrAnchorTooFar is an anchor of an arm or a leg drawn away from the
+	// outline of the body
+	ErrAnchorTooFar = errors.New("anchor too far from the body outline")
+	// ErrUnmappedColor is a color drawn in a sheet without a palette slot
+	ErrUnmappedColor = errors.New("color has no palette slot")
+)
+
+// problems reported by Lint, most of them do not stop Sort from extracting
+var (
+	ErrTypedLabel     = errors.New(`label starts with "type@", the type is the label of the root anchor`)
+	ErrManyAnchors    = errors.New("more than one root anchor")
+	ErrNestedAnchor   = errors.New("root anchor must be a direct child of the layer")
+	ErrUnknownType    = errors.New("unknown bodypart type")
+	ErrNoFirstFrame   = errors.New("no frame 0")
+	ErrMissingFrame   = errors.New("missing frame")
+	ErrDuplicateFrame = errors.New("duplicate frame")
+	ErrUnpaired       = errors.New("arms and legs must come in pairs")
+	ErrDuplicateSide  = errors.New("duplicate anchor side")
+	ErrAnchorMismatch = errors.New("anchors differ between frames")
+	ErrMissingAnchor  = errors.New("no anchor for bodypart type")
+)
+
+// LayerError is a problem found while extracting a top-level layer of a
+// sheet, Element is the id of the offending element inside the layer if any.
+// Warning marks the problems that do not stop the layer from being extracted.
+type LayerError struct {
+	LayerID string
+	Label   string
+	Element string
+	Err     error
+	Warning bool
+}
+
+func (e *LayerError) Error() string {
+	msg := "layer " + strconv.Quote(e.LayerID) + " (" + strconv.Quote(e.Label) + ")"
+	if e.Element != "" {
+		msg += ": element " + strconv.Quote(e.Element)
+	}
+	return msg + ": " + e.Err.Error()
+}
+
+func (e *LayerError) Unwrap() error {
+	return e.Err
+}
+
+// Errors collects every problem found while extracting a sheet
+type Errors []*LayerError
+
+func (e Errors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Failures leaves the warnings out
+func (e Errors) Failures() Errors {
+	failures := make(Errors, 0, len(e))
+	for _, err := range e {
+		if !err.Warning {
+			failures = append(failures, err)
+		}
+	}
+	return failures
+}
+
+func (e Errors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, err := range e {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// layerError ties err to the layer it was found in, taking the element id
+// from err when the document model reported one
+func layerError(layer svgdoc.Group, err error) *LayerError {
+	result := &LayerError{LayerID: layer.ID, Label: layer.Label, Err: err}
+	var elementErr *svgdoc.ElementError
+	if errors.As(err, &elementErr) {
+		result.Element = elementErr.ID
+		result.Err = elementErr.Err
+	}
+	return result
+}
@@ -0,0 +1,480 @@
+// Package extract sorts the top-level layers of an art sheet into bodies and
+// bodyparts.
+package extract
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"tama/geom"
+	"tama/pet"
+	"tama/svgdoc"
+)
+
+var frameReg = regexp.MustCompile("^(.+)-([0-9]+)$")
+
+// parseLabel splits a layer label into its name and frame
+func parseLabel(label string) (string, int, error) {
+	matches := frameReg.FindStringSubmatch(label)
+	if len(matches) < 3 {
+		return "", 0, ErrBadLabel
+	}
+	frame, err := strconv.Atoi(matches[2])
+	if err != nil {
+		return "", 0, ErrBadLabel
+	}
+	return matches[1], frame, nil
+}
+
+// snapDistance is how far from the outline an anchor can be drawn, it is
+// moved onto the outline
+const snapDistance = 2
+
+// findClosestPointInPaths projects point on the outline drawn by paths and
+// returns the signed area of the subpath it lands on, the result is
+// ErrAnchorTooFar when the outline is farther than rng
+func findClosestPointInPaths(paths []svgdoc.Path, point geom.Point, rng float64) (geom.Projection, float64, error) {
+	var best geom.Projection
+	area := 0.0
+	found := false
+	for _, p := range paths {
+		commands, err := geom.ParseD(p.D)
+		if err != nil {
+			return geom.Projection{}, 0, &svgdoc.ElementError{ID: p.ID, Err: err}
+		}
+		for _, subpath := range geom.GetSubpathsFromCommands(commands) {
+			_, projection, ok := geom.ProjectOnBeziers(subpath, point)
+			if ok && (!found || projection.Distance < best.Distance) {
+				best, area, found = projection, geom.SignedArea(subpath), true
+			}
+		}
+	}
+	if !found {
+		return geom.Projection{}, 0, ErrNoPath
+	}
+	if best.Distance > rng {
+		return best, area, fmt.Errorf("%w: %.2f away", ErrAnchorTooFar, best.Distance)
+	}
+	return best, area, nil
+}
+
+// anchorAngle is the rotation in degrees of a bodypart pinned on an outline
+// with this outward normal. Bodyparts point up once normalized (see
+// GroupNormalizeRotation), the rotation turns up into the normal.
+func anchorAngle(normal geom.Point) float64 {
+	return math.Atan2(normal.X, -normal.Y) * 180 / math.Pi
+}
+
+// anchor is a point found in a body before it is placed on the outline
+type anchor struct {
+	id    string
+	point pet.Point
+}
+
+func newAnchor(id, label string, x, y float64) anchor {
+	t, side := pet.ParseAnchorLabel(label)
+	return anchor{id: id, point: pet.Point{X: x, Y: y, Type: t, Side: side}}
+}
+
+// collectAnchors removes every ellipse and circle not labelled rootLabel from
+// the group tree and returns them as anchors
+func collectAnchors(group *svgdoc.Group, rootLabel string) []anchor {
+	anchors := make([]anchor, 0)
+	for i := range group.Groups {
+		anchors = append(anchors, collectAnchors(&group.Groups[i], rootLabel)...)
+	}
+	i := 0
+	for _, e := range group.Ellipses {
+		if e.Label == rootLabel {
+			group.Ellipses[i] = e
+			i++
+		} else {
+			anchors = append(anchors, newAnchor(e.ID, e.Label, e.CX, e.CY))
+		}
+	}
+	group.Ellipses = group.Ellipses[:i]
+	i = 0
+	for _, e := range group.Circles {
+		if e.Label == rootLabel {
+			group.Circles[i] = e
+			i++
+		} else {
+			anchors = append(anchors, newAnchor(e.ID, e.Label, e.CX, e.CY))
+		}
+	}
+	group.Circles = group.Circles[:i]
+	return anchors
+}
+
+func RetrievePoints(group *svgdoc.Group, rootLabel string, types pet.Registry) ([]pet.Point, error) {
+	anchors := collectAnchors(group, rootLabel)
+
+	// Place anchor on exact body point
+	paths := svgdoc.GetPathsInGroup(*group)
+	points := make([]pet.Point, 0, len(anchors))
+	for _, a := range anchors {
+		point := a.point
+		if !types.Inside(point.Type) {
+			projection, area, err := findClosestPointInPaths(paths, point.Position(), snapDistance)
+			if err != nil {
+				return nil, &svgdoc.ElementError{ID: a.id, Err: fmt.Errorf("%s anchor: %w", point.Type, err)}
+			}
+			point.X = projection.Position.X
+			point.Y = projection.Position.Y
+			point.T = anchorAngle(geom.OutwardNormal(projection.Tangent, area))
+		}
+		points = append(points, point)
+	}
+
+	identifyAnchors(points, types)
+	return points, nil
+}
+
+// identifyAnchors sorts the anchors by type then from left to right, and
+// tells them apart. Two anchors of a type, or an arm1 and an arm2, are given
+// the side they are drawn on unless their label gives it. The right one is
+// mirrored when the type draws one bodypart on both sides.
+func identifyAnchors(points []pet.Point, types pet.Registry) {
+	slices.SortStableFunc(points, func(a pet.Point, b pet.Point) int {
+		if order := types.Compare(a.Type, b.Type); order != 0 {
+			return order
+		}
+		return cmp.Compare(a.X, b.X)
+	})
+
+	sides := make(map[pet.BodypartType][]int)
+	for i, p := range points {
+		key := p.Type
+		if other, ok := types.Pair(p.Type); ok && types.Order(other) < types.Order(key) {
+			key = other
+		}
+		sides[key] = append(sides[key], i)
+	}
+	for _, indices := range sides {
+		if len(indices) != 2 {
+			continue
+		}
+		a, b := &points[indices[0]], &points[indices[1]]
+		if a.X > b.X {
+			a, b = b, a
+		}
+		switch {
+		case a.Side == pet.SideNone && b.Side == pet.SideNone && a.X != b.X:
+			a.Side, b.Side = pet.SideLeft, pet.SideRight
+		case a.Side == pet.SideNone && b.Side != pet.SideNone:
+			a.Side = opposite(b.Side)
+		case b.Side == pet.SideNone && a.Side != pet.SideNone:
+			b.Side = opposite(a.Side)
+		}
+	}
+
+	for i := range points {
+		if i > 0 && points[i].Type == points[i-1].Type {
+			points[i].Index = points[i-1].Index + 1
+		}
+		points[i].Mirror = points[i].Side == pet.SideRight && types.Mirrored(points[i].Type)
+	}
+}
+
+func opposite(side pet.Side) pet.Side {
+	if side == pet.SideLeft {
+		return pet.SideRight
+	}
+	return pet.SideLeft
+}
+
+func parseBody(g svgdoc.Group, opts Options) (pet.Body, error) {
+	name, frame, err := parseLabel(g.Label)
+	if err != nil {
+		return pet.Body{}, err
+	}
+	group := svgdoc.GroupCopy(g)
+	group.ID = group.Label
+	group.Label = "body"
+	// move the body so its bounding box starts at the origin
+	bb, err := group.GetBoundingBox()
+	if err != nil {
+		return pet.Body{}, err
+	}
+	group, err = group.Transform(geom.TranslateMatrix(-bb.TopLeft.X, -bb.TopLeft.Y))
+	if err != nil {
+		return pet.Body{}, err
+	}
+	anchors, err := RetrievePoints(&group, group.Label, opts.Types)
+	if err != nil {
+		return pet.Body{}, err
+	}
+	bb, err = group.GetBoundingBox()
+	if err != nil {
+		return pet.Body{}, err
+	}
+	paths, err := subPaths(group, pet.SlotFill1, opts)
+	if err != nil {
+		return pet.Body{}, err
+	}
+	return pet.Body{
+		Path:        group.GetPath().D,
+		Paths:       paths,
+		Points:      anchors,
+		Frame:       frame,
+		Name:        name,
+		BoundingBox: bb,
+	}, nil
+}
+
+// do not considere paths
+func findElementPosition(group svgdoc.Group, name string) geom.Point {
+	for _, el := range group.Ellipses {
+		if el.Label == name {
+			return geom.Point{X: el.CX, Y: el.CY}
+		}
+	}
+	for _, ci := range group.Circles {
+		if ci.Label == name {
+			return geom.Point{X: ci.CX, Y: ci.CY}
+		}
+	}
+	for _, g := range group.Groups {
+		p := findElementPosition(g, name)
+		if p.X != math.MaxFloat64 && p.Y != math.MaxFloat64 {
+			return p
+		}
+	}
+	return geom.Point{X: math.MaxFloat64, Y: math.MaxFloat64}
+}
+
+// GroupNormalizeRotation turns a bodypart whose root anchor is at the origin
+// so that it points up: the direction it grows in from the anchor becomes
+// (0, -1). On a closed outline it is the inward normal of the outline around
+// the anchor, an open stroke, whose normal tells nothing, points to its
+// farthest point.
+func GroupNormalizeRotation(group svgdoc.Group) (svgdoc.Group, error) {
+	origin := geom.Point{X: 0, Y: 0}
+	var best geom.Projection
+	var outline []geom.Bezier
+	found := false
+	for _, path := range svgdoc.GetPathsInGroup(group) {
+		commands, err := geom.ParseD(path.D)
+		if err != nil {
+			return svgdoc.Group{}, &svgdoc.ElementError{ID: path.ID, Err: err}
+		}
+		for _, subpath := range geom.GetSubpathsFromCommands(commands) {
+			_, projection, ok := geom.ProjectOnBeziers(subpath, origin)
+			if ok && (!found || projection.Distance < best.Distance) {
+				best, outline, found = projection, subpath, true
+			}
+		}
+	}
+	if !found {
+		return group, nil
+	}
+
+	direction := farthestPoint(outline)
+	first, last := outline[0].P0, outline[len(outline)-1].P3
+	if area := geom.SignedArea(outline); first.Sub(last).Length() < 1e-6 && math.Abs(area) > 1e-6 {
+		// the anchor is drawn inside the outline, on thin bodyparts the
+		// closest point may be on a side, the normals around it average out
+		normal := meanNormal(outline, area, snapDistance)
+		if normal.X != 0 || normal.Y != 0 {
+			direction = geom.Point{X: -normal.X, Y: -normal.Y}
+		}
+	}
+	if direction.X == 0 && direction.Y == 0 {
+		return group, nil
+	}
+	return group.Transform(geom.RotateMatrix(-anchorAngle(direction)))
+}
+
+// meanNormal sums the outward normal of the outline along the parts less than
+// radius away from the origin, weighted by their length
+func meanNormal(outline []geom.Bezier, area, radius float64) geom.Point {
+	var sum geom.Point
+	for _, bz := range outline {
+		for t := 0.025; t < 1; t += 0.05 {
+			if bz.At(t).Length() > radius {
+				continue
+			}
+			normal := geom.OutwardNormal(bz.Derivative(t), area)
+			sum.X += normal.X
+			sum.Y += normal.Y
+		}
+	}
+	return sum
+}
+
+// farthestPoint returns the point of the outline farthest from the origin,
+// sampled along every curve
+func farthestPoint(outline []geom.Bezier) geom.Point {
+	var farthest geom.Point
+	for _, bz := range outline {
+		for t := 0.0; t <= 1.0; t += 0.05 {
+			if p := bz.At(t); p.Length() > farthest.Length() {
+				farthest = p
+			}
+		}
+	}
+	return farthest
+}
+
+func parseBodypart(g svgdoc.Group, opts Options) (pet.BodyPart, error) {
+	name, frame, err := parseLabel(g.Label)
+	if err != nil {
+		return pet.BodyPart{}, err
+	}
+	group := svgdoc.GroupCopy(g)
+	group.ID = group.Label
+	switch {
+	case len(g.Ellipses) > 0:
+		group.Label = group.Ellipses[0].Label
+	case len(g.Circles) > 0:
+		group.Label = group.Circles[0].Label
+	default:
+		return pet.BodyPart{}, ErrNoAnchor
+	}
+	anchor := findElementPosition(group, group.Label)
+	group, err = group.Transform(geom.TranslateMatrix(anchor.X*-1, anchor.Y*-1))
+	if err != nil {
+		return pet.BodyPart{}, err
+	}
+	svgdoc.CleanGroup(&group)
+	if opts.Types.Normalized(pet.BodypartType(group.Label)) {
+		group, err = GroupNormalizeRotation(group)
+		if err != nil {
+			return pet.BodyPart{}, err
+		}
+	}
+	path := group.GetPath()
+	bb, err := path.GetBoundingBox()
+	if err != nil {
+		return pet.BodyPart{}, err
+	}
+	paths, err := subPaths(group, pet.SlotFill2, opts)
+	if err != nil {
+		return pet.BodyPart{}, err
+	}
+	return pet.BodyPart{
+		BoundingBox: bb,
+		Path:        path.D,
+		Paths:       paths,
+		Type:        pet.BodypartType(group.Label),
+		Frame:       frame,
+		Name:        name,
+	}, nil
+}
+
+// subPaths keeps the style of every path of the group, its colors mapped to
+// palette slots. Paths without fill are filled with base like the renderer
+// does, so are colors without a slot, outlined with the stroke slot, unless
+// opts.StrictColors reports them with the path.
+func subPaths(group svgdoc.Group, base string, opts Options) ([]pet.SubPath, error) {
+	paths := svgdoc.GetPathsInGroup(group)
+	result := make([]pet.SubPath, 0, len(paths))
+	for _, path := range paths {
+		style := path.ParseStyle()
+		sub := pet.SubPath{
+			ID:          path.ID,
+			Path:        path.D,
+			Fill:        base,
+			StrokeWidth: style.StrokeWidth,
+			Opacity:     style.Opacity,
+			FillRule:    style.FillRule,
+		}
+		var err error
+		if style.Fill != "none" {
+			if sub.Fill, err = colorSlot(opts, style.Fill, base); err != nil {
+				return nil, &svgdoc.ElementError{ID: path.ID, Err: fmt.Errorf("fill: %w", err)}
+			}
+		}
+		if style.Stroke != "none" {
+			if sub.Stroke, err = colorSlot(opts, style.Stroke, pet.SlotStroke); err != nil {
+				return nil, &svgdoc.ElementError{ID: path.ID, Err: fmt.Errorf("stroke: %w", err)}
+			}
+		}
+		result = append(result, sub)
+	}
+	return result, nil
+}
+
+// colorSlot returns the slot of a color, fallback when it has none unless
+// opts.StrictColors
+func colorSlot(opts Options, color, fallback string) (string, error) {
+	slot, ok := opts.Colors.Slot(color)
+	switch {
+	case ok:
+		return slot, nil
+	case opts.StrictColors:
+		return "", fmt.Errorf("%w %s", ErrUnmappedColor, color)
+	}
+	return fallback, nil
+}
+
+func isBody(group svgdoc.Group) bool {
+	return slices.ContainsFunc(group.Paths, func(p svgdoc.Path) bool { return p.Label == "body" })
+}
+
+// Options tells how the layers of a sheet are extracted, the zero Options
+// extracts with pet.DefaultRegistry and pet.DefaultColorSlots
+type Options struct {
+	// Types places the anchors of bodies and turns the bodyparts
+	Types pet.Registry
+	// Colors gives the palette slot of the colors drawn in the sheet
+	Colors pet.ColorSlots
+	// StrictColors fails the layers drawing a color without a slot, they are
+	// otherwise drawn with the slots of unstyled paths
+	StrictColors bool
+}
+
+// withDefaults fills the types and colors left empty with the built-in ones
+func (opts Options) withDefaults() Options {
+	if len(opts.Types.Types()) == 0 {
+		opts.Types = pet.DefaultRegistry
+	}
+	if opts.Colors == nil {
+		opts.Colors = pet.DefaultColorSlots
+	}
+	return opts
+}
+
+// Sort extracts every top-level layer of root, layers that can't be extracted
+// are left out of the results and reported together as Errors
+func Sort(root svgdoc.SVG, opts Options) ([]pet.Body, []pet.BodyPart, error) {
+	opts = opts.withDefaults()
+	bodies := make([]pet.Body, 0)
+	bodyparts := make([]pet.BodyPart, 0)
+	errs := make(Errors, 0)
+	for _, layer := range root.Groups {
+		// from here on every coordinate of the layer is absolute
+		group, err := layer.ResolveTransforms(geom.Identity())
+		if err != nil {
+			errs = append(errs, layerError(layer, err))
+			continue
+		}
+		if len(svgdoc.GetPathsInGroup(group)) == 0 {
+			errs = append(errs, layerError(group, ErrNoPath))
+			continue
+		}
+		if isBody(group) {
+			body, err := parseBody(group, opts)
+			if err != nil {
+				errs = append(errs, layerError(group, err))
+				continue
+			}
+			bodies = append(bodies, body)
+		} else {
+			bodypart, err := parseBodypart(group, opts)
+			if err != nil {
+				errs = append(errs, layerError(group, err))
+				continue
+			}
+			bodyparts = append(bodyparts, bodypart)
+		}
+	}
+	if len(errs) > 0 {
+		return bodies, bodyparts, errs
+	}
+	return bodies, bodyparts, nil
+}
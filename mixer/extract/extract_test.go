@@ -0,0 +1,382 @@
+package extract
+
+import (
+	"errors"
+	"maps"
+	"math"
+	"testing"
+
+	"tama/geom"
+	"tama/pet"
+	"tama/svgdoc"
+)
+
+var testOptions = Options{Types: pet.DefaultRegistry, Colors: pet.DefaultColorSlots}
+
+func TestFindClosestPointInPathSimple(t *testing.T) {
+	paths := []svgdoc.Path{{D: "M 130 10 C 120 20, 180 20, 170 10"}}
+	got, _, err := findClosestPointInPaths(
+		paths,
+		geom.Point{X: 130, Y: 10},
+		1,
+	)
+	want := 0.0
+	if err != nil || got.T != want {
+		t.Errorf("Got %f (%v) expected %f", got.T, err, want)
+	}
+	got, _, err = findClosestPointInPaths(
+		paths,
+		geom.Point{X: 170, Y: 10},
+		1,
+	)
+	want = 1.0
+	if err != nil || got.T != want {
+		t.Errorf("Got %f (%v) expected %f", got.T, err, want)
+	}
+}
+
+func TestFindClosestPointInPathComplex(t *testing.T) {
+	paths := []svgdoc.Path{{D: "m 0 0 c 2.384706 -3.8247189 9.090522 -2.8303014 13.419508 -2.9698914 4.328986 -0.13959 8.777591 -0.1227708 12.099557 1.8699314"}}
+
+	got, _, err := findClosestPointInPaths(
+		paths,
+		geom.Point{X: 0, Y: 0},
+		1,
+	)
+	want := 0.0
+	if err != nil || got.T != want {
+		t.Errorf("Got %f (%v) expected %f", got.T, err, want)
+	}
+	got, _, err = findClosestPointInPaths(
+		paths,
+		geom.Point{X: 0 + 13.419508 + 12.099557, Y: 0 - 2.9698914 + 1.8699314},
+		1,
+	)
+	want = 1.0
+	if err != nil || got.T != want {
+		t.Errorf("Got %f (%v) expected %f", got.T, err, want)
+	}
+}
+
+func TestFindClosestPointInPathClosest(t *testing.T) {
+	// both curves are in range, the second one is closer
+	paths := []svgdoc.Path{{D: "M 0 0 L 10 0"}, {D: "M 0 1 L 10 1"}}
+	got, _, err := findClosestPointInPaths(
+		paths,
+		geom.Point{X: 4, Y: 0.8},
+		1,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(got.Position.X-4) > 1e-9 || math.Abs(got.Position.Y-1) > 1e-9 {
+		t.Errorf("Got %v expected (4, 1)", got.Position)
+	}
+	if math.Abs(got.Distance-0.2) > 1e-9 {
+		t.Errorf("Got distance %f expected 0.2", got.Distance)
+	}
+}
+
+func TestFindClosestPointInPathError(t *testing.T) {
+	paths := []svgdoc.Path{{D: "M 130 10 C 120 20, 180 20, 170 10"}}
+	_, _, err := findClosestPointInPaths(
+		paths,
+		geom.Point{X: 0, Y: 10},
+		1,
+	)
+	if !errors.Is(err, ErrAnchorTooFar) {
+		t.Errorf("Got %v expected %v", err, ErrAnchorTooFar)
+	}
+}
+
+func TestSortCollectsErrors(t *testing.T) {
+	sheet := svgdoc.SVG{Groups: []svgdoc.Group{
+		{ID: "layer1", Label: "stick-0", Paths: []svgdoc.Path{{ID: "path1", D: "M 0 0 L 10 10"}}, Ellipses: []svgdoc.Ellipse{{Label: "arm1"}}},
+		{ID: "layer2", Label: "stick", Paths: []svgdoc.Path{{ID: "path2", D: "M 0 0 L 10 10"}}, Ellipses: []svgdoc.Ellipse{{Label: "arm1"}}},
+		{ID: "layer3", Label: "stick-1", Paths: []svgdoc.Path{{ID: "path3", D: "M 0 0 L 10 10"}}},
+		{ID: "layer4", Label: "ball-0", Paths: []svgdoc.Path{{ID: "path4", Label: "body", D: "M 0 0 L 10 x"}}},
+		{ID: "layer5", Label: "empty-0"},
+		{ID: "layer6", Label: "mush-0", Paths: []svgdoc.Path{{ID: "path6", Label: "body", D: "M 0 0 L 10 0 L 10 10 Z"}}, Ellipses: []svgdoc.Ellipse{{ID: "ellipse6", Label: "arm1", CX: 20, CY: 20}}},
+	}}
+	bodies, bodyparts, err := Sort(sheet, testOptions)
+	if len(bodies) != 0 || len(bodyparts) != 1 {
+		t.Errorf("expected only the valid bodypart to be extracted, got %d bodies and %d bodyparts", len(bodies), len(bodyparts))
+	}
+	var errs Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected Errors, got %v", err)
+	}
+	want := []struct {
+		layerID string
+		element string
+		err     error
+	}{
+		{"layer2", "", ErrBadLabel},
+		{"layer3", "", ErrNoAnchor},
+		{"layer4", "path4", nil},
+		{"layer5", "", ErrNoPath},
+		{"layer6", "ellipse6", ErrAnchorTooFar},
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("expected %d errors, got %d: %v", len(want), len(errs), err)
+	}
+	for i, w := range want {
+		if errs[i].LayerID != w.layerID || errs[i].Element != w.element {
+			t.Errorf("error %d: got layer %q element %q, expected layer %q element %q", i, errs[i].LayerID, errs[i].Element, w.layerID, w.element)
+		}
+		if w.err != nil && !errors.Is(errs[i], w.err) {
+			t.Errorf("error %d: got %v, expected %v", i, errs[i].Err, w.err)
+		}
+	}
+	var syntaxErr *geom.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Errorf("expected the path data error to be reachable, got %v", err)
+	}
+}
+
+func TestSortNestedAnchors(t *testing.T) {
+	sheet := svgdoc.SVG{Groups: []svgdoc.Group{{
+		ID:    "layer1",
+		Label: "ball-0",
+		Paths: []svgdoc.Path{{ID: "path1", Label: "body", D: "M 10 10 L 30 10 L 30 30 L 10 30 Z"}},
+		Groups: []svgdoc.Group{{
+			ID:       "eyes",
+			Ellipses: []svgdoc.Ellipse{{Label: "eye", CX: 15, CY: 15}, {Label: "eye", CX: 25, CY: 15}},
+			Groups: []svgdoc.Group{{
+				ID:      "mouth",
+				Circles: []svgdoc.Circle{{Label: "mouth", CX: 20, CY: 25}},
+			}},
+		}},
+	}}}
+	bodies, _, err := Sort(sheet, testOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	points := bodies[0].Points
+	if len(points) != 3 {
+		t.Fatalf("expected 3 anchors, got %v", points)
+	}
+	if points[0].Type != "eye" || points[1].Type != "eye" || points[2].Type != "mouth" {
+		t.Errorf("wrong anchor types %v", points)
+	}
+	if points[2].X != 10 || points[2].Y != 15 {
+		t.Errorf("mouth is on (%f, %f) expected (10, 15)", points[2].X, points[2].Y)
+	}
+}
+
+func TestSortZeroOptions(t *testing.T) {
+	// the eye stays inside the body, as with the built-in types
+	sheet := svgdoc.SVG{Groups: []svgdoc.Group{{
+		ID:      "layer1",
+		Label:   "ball-0",
+		Paths:   []svgdoc.Path{{ID: "path1", Label: "body", D: "M 10 10 L 30 10 L 30 30 L 10 30 Z", Style: "fill:#ffffff"}},
+		Circles: []svgdoc.Circle{{Label: "eye", CX: 20, CY: 20}},
+	}}}
+	bodies, _, err := Sort(sheet, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if points := bodies[0].Points; len(points) != 1 || points[0].X != 10 || points[0].Y != 10 {
+		t.Errorf("got anchors %v expected the eye on (10, 10)", points)
+	}
+	if paths := bodies[0].Paths; len(paths) != 1 || paths[0].Fill != pet.SlotHighlight {
+		t.Errorf("got paths %+v expected a highlight fill", paths)
+	}
+}
+
+func TestSortBodyBoundingBox(t *testing.T) {
+	// the curve bulges left of its end points, to x = -7.5
+	sheet := svgdoc.SVG{Groups: []svgdoc.Group{{
+		ID:    "layer1",
+		Label: "ball-0",
+		Paths: []svgdoc.Path{{ID: "path1", Label: "body", D: "M 0 -10 C -10 -10 -10 10 0 10 L 20 10 Z"}},
+	}}}
+	bodies, _, err := Sort(sheet, testOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := bodies[0].BoundingBox
+	want := geom.Rect{TopLeft: geom.Point{X: 0, Y: 0}, BottomRight: geom.Point{X: 27.5, Y: 20}}
+	if math.Abs(got.TopLeft.X-want.TopLeft.X) > 1e-9 || math.Abs(got.TopLeft.Y-want.TopLeft.Y) > 1e-9 ||
+		math.Abs(got.BottomRight.X-want.BottomRight.X) > 1e-9 || math.Abs(got.BottomRight.Y-want.BottomRight.Y) > 1e-9 {
+		t.Errorf("got %v expected %v", got, want)
+	}
+}
+
+func TestSortAnchorAngles(t *testing.T) {
+	// a body with a notch at the bottom, legs in the notch point into it
+	sheet := svgdoc.SVG{Groups: []svgdoc.Group{{
+		ID:    "layer1",
+		Label: "notch-0",
+		Paths: []svgdoc.Path{{ID: "path1", Label: "body", D: "M 0 0 L 30 0 L 30 30 L 20 30 L 20 10 L 10 10 L 10 30 L 0 30 Z"}},
+		Ellipses: []svgdoc.Ellipse{
+			{Label: "arm1", CX: 0, CY: 15},
+			{Label: "arm2", CX: 20, CY: 20},
+			{Label: "leg1", CX: 15, CY: 10.5},
+			{Label: "leg2", CX: 25, CY: 30},
+		},
+	}}}
+	bodies, _, err := Sort(sheet, testOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[pet.BodypartType]float64{"arm1": -90, "arm2": -90, "leg1": 180, "leg2": 180}
+	for _, point := range bodies[0].Points {
+		if math.Abs(point.T-want[point.Type]) > 1e-6 {
+			t.Errorf("%s: got %f expected %f", point.Type, point.T, want[point.Type])
+		}
+	}
+}
+
+func TestGroupNormalizeRotation(t *testing.T) {
+	tests := []struct {
+		name string
+		d    string
+	}{
+		// the anchor is inside the end of a thin outline, its closest point is on a side
+		{"stick to the right", "M -0.5 -0.4 L 10 -0.4 L 10 0.4 L -0.5 0.4 Z"},
+		{"blob to the bottom left", "M -2 -2 L 2 2 L -2 6 L -6 2 Z"},
+		{"open stroke down", "M 0 0 L 0 4 L 1 8"},
+	}
+	for _, tc := range tests {
+		group := svgdoc.Group{Paths: []svgdoc.Path{{ID: "path1", D: tc.d}}}
+		group, err := GroupNormalizeRotation(group)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bb, err := group.GetBoundingBox()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// pointing up, it stays around the vertical of the anchor
+		if bb.BottomRight.Y > 1 || bb.TopLeft.Y > -5 || bb.TopLeft.X < -3 || bb.BottomRight.X > 3 {
+			t.Errorf("%s: got bounding box %v", tc.name, bb)
+		}
+	}
+}
+
+func TestSortPartTypes(t *testing.T) {
+	types, err := pet.NewRegistry([]pet.TypeInfo{{Type: "spot", Inside: true}, {Type: "antenna", Normalize: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sheet := svgdoc.SVG{Groups: []svgdoc.Group{{
+		ID:    "layer1",
+		Label: "box-0",
+		Paths: []svgdoc.Path{{ID: "path1", Label: "body", D: "M 0 0 L 10 0 L 10 10 L 0 10 Z"}},
+		Circles: []svgdoc.Circle{
+			{ID: "wings", Label: "wings", CX: 10, CY: 5},
+			{ID: "antenna", Label: "antenna", CX: 5, CY: 0.5},
+			{ID: "spot", Label: "spot", CX: 5, CY: 5},
+		},
+	}}}
+	bodies, _, err := Sort(sheet, Options{Types: types, Colors: pet.DefaultColorSlots})
+	if err != nil {
+		t.Fatal(err)
+	}
+	points := bodies[0].Points
+	if len(points) != 3 || points[0].Type != "spot" || points[1].Type != "antenna" || points[2].Type != "wings" {
+		t.Fatalf("expected spot, antenna then the unknown wings, got %v", points)
+	}
+	if points[0].Y != 5 {
+		t.Errorf("spot is drawn inside, it moved to %v", points[0])
+	}
+	if math.Abs(points[1].Y) > 1e-9 {
+		t.Errorf("antenna is not on the outline: %v", points[1])
+	}
+}
+
+func TestSortAnchorSides(t *testing.T) {
+	sheet := svgdoc.SVG{Groups: []svgdoc.Group{{
+		ID:    "layer1",
+		Label: "box-0",
+		Paths: []svgdoc.Path{{ID: "path1", Label: "body", D: "M 0 0 L 10 0 L 10 10 L 0 10 Z"}},
+		Circles: []svgdoc.Circle{
+			{Label: "eye", CX: 7, CY: 3},
+			{Label: "eye", CX: 3, CY: 3},
+			{Label: "arm2", CX: 10, CY: 5},
+			{Label: "arm1", CX: 0, CY: 5},
+			{Label: "mouth.left", CX: 4, CY: 7},
+			{Label: "mouth", CX: 6, CY: 7},
+			{Label: "horns", CX: 5, CY: 0},
+		},
+	}}}
+	bodies, _, err := Sort(sheet, testOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []pet.Point{
+		{X: 3, Type: "eye", Side: pet.SideLeft},
+		{X: 7, Type: "eye", Side: pet.SideRight, Index: 1, Mirror: true},
+		{X: 4, Type: "mouth", Side: pet.SideLeft},
+		{X: 6, Type: "mouth", Side: pet.SideRight, Index: 1},
+		{X: 0, Type: "arm1", Side: pet.SideLeft},
+		{X: 10, Type: "arm2", Side: pet.SideRight},
+		{X: 5, Type: "horns"},
+	}
+	points := bodies[0].Points
+	if len(points) != len(want) {
+		t.Fatalf("got %v expected %v", points, want)
+	}
+	for i, w := range want {
+		p := points[i]
+		if p.Type != w.Type || math.Abs(p.X-w.X) > 1e-9 || p.Side != w.Side || p.Index != w.Index || p.Mirror != w.Mirror {
+			t.Errorf("anchor %d is %+v expected %+v", i, p, w)
+		}
+	}
+}
+
+func TestSortSubPaths(t *testing.T) {
+	sheet := svgdoc.SVG{Groups: []svgdoc.Group{{
+		ID:    "layer1",
+		Label: "star-0",
+		Paths: []svgdoc.Path{
+			{ID: "outline", D: "M 0 0 L 0 -4", Style: "fill:none;stroke:#000000;stroke-width:0.5"},
+			{ID: "pupil", D: "M 0 -1 L 1 -1 L 1 -2 Z", Style: "fill:#000;stroke:none"},
+			{ID: "shine", D: "M 0 -3 L 1 -3", Style: "fill:white;opacity:0.5;fill-rule:evenodd"},
+			{ID: "spot", D: "M 0 -2 L 1 -2", Style: "fill:#ff0000;stroke:#00ff00"},
+		},
+		Ellipses: []svgdoc.Ellipse{{Label: "eye"}},
+	}}}
+	// red and green are not mapped by default, they fail the layer only when
+	// asked to
+	strict := testOptions
+	strict.StrictColors = true
+	_, _, err := Sort(sheet, strict)
+	var errs Errors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Element != "spot" || !errors.Is(err, ErrUnmappedColor) {
+		t.Fatalf("expected ErrUnmappedColor on spot, got %v", err)
+	}
+	_, bodyparts, err := Sort(sheet, testOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spot := bodyparts[0].Paths[3]; spot.Fill != pet.SlotFill2 || spot.Stroke != pet.SlotStroke {
+		t.Errorf("got %+v expected the slots of an unstyled bodypart", spot)
+	}
+
+	opts := testOptions
+	opts.Colors = maps.Clone(pet.DefaultColorSlots)
+	opts.Colors["#ff0000"] = pet.SlotAccent
+	opts.Colors["#00ff00"] = pet.SlotFill1
+	_, bodyparts, err = Sort(sheet, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []pet.SubPath{
+		{ID: "outline", Fill: pet.SlotFill2, Stroke: pet.SlotStroke, StrokeWidth: 0.5, Opacity: 1, FillRule: "nonzero"},
+		{ID: "pupil", Fill: pet.SlotStroke, Opacity: 1, FillRule: "nonzero"},
+		{ID: "shine", Fill: pet.SlotHighlight, Opacity: 0.5, FillRule: "evenodd"},
+		{ID: "spot", Fill: pet.SlotAccent, Stroke: pet.SlotFill1, StrokeWidth: 1, Opacity: 1, FillRule: "nonzero"},
+	}
+	paths := bodyparts[0].Paths
+	if len(paths) != len(want) {
+		t.Fatalf("got %v expected %v", paths, want)
+	}
+	for i, w := range want {
+		w.Path = paths[i].Path
+		if paths[i] != w || paths[i].Path == "" {
+			t.Errorf("got %+v expected %+v", paths[i], w)
+		}
+	}
+}
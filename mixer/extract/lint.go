@@ -0,0 +1,271 @@
+package extract
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"tama/pet"
+	"tama/svgdoc"
+)
+
+// lintAnchor is an ellipse or a circle of a layer, top when it is a direct
+// child of the layer
+type lintAnchor struct {
+	id    string
+	label string
+	top   bool
+}
+
+// layerAnchors lists the ellipses and circles of a layer in the order
+// parseBodypart looks for its root anchor
+func layerAnchors(group svgdoc.Group, top bool) []lintAnchor {
+	anchors := make([]lintAnchor, 0)
+	for _, e := range group.Ellipses {
+		anchors = append(anchors, lintAnchor{id: e.ID, label: e.Label, top: top})
+	}
+	for _, c := range group.Circles {
+		anchors = append(anchors, lintAnchor{id: c.ID, label: c.Label, top: top})
+	}
+	for _, g := range group.Groups {
+		anchors = append(anchors, layerAnchors(g, false)...)
+	}
+	return anchors
+}
+
+// lintKey identifies the frames of a body, or of a bodypart of a type
+type lintKey struct {
+	body bool
+	typ  pet.BodypartType
+	name string
+}
+
+// lintLayer is a layer whose label and type could be read
+type lintLayer struct {
+	index   int
+	group   svgdoc.Group
+	frame   int
+	anchors map[pet.BodypartType]int
+}
+
+type lintIssue struct {
+	index int
+	err   *LayerError
+}
+
+type linter struct {
+	opts   Options
+	types  pet.Registry
+	keys   []lintKey
+	layers map[lintKey][]lintLayer
+	issues []lintIssue
+}
+
+func (l *linter) report(layer lintLayer, element string, err error) {
+	l.issues = append(l.issues, lintIssue{layer.index, &LayerError{LayerID: layer.group.ID, Label: layer.group.Label, Element: element, Err: err}})
+}
+
+// warn reports a problem Sort gets around
+func (l *linter) warn(layer lintLayer, element string, err error) {
+	l.report(layer, element, err)
+	l.issues[len(l.issues)-1].err.Warning = true
+}
+
+// Lint checks the structure of every top-level layer of root without
+// extracting it: "name-frame" labels, a single root anchor of a known type
+// per bodypart, frames numbered from 0 without gaps, arms and legs drawn in
+// pairs, and bodies anchoring the same bodyparts in every frame. Colors
+// without a palette slot are warnings unless opts.StrictColors. Every problem
+// is returned, in the order of the layers.
+func Lint(root svgdoc.SVG, opts Options) Errors {
+	opts = opts.withDefaults()
+	l := linter{opts: opts, types: opts.Types, layers: make(map[lintKey][]lintLayer)}
+	for i, group := range root.Groups {
+		l.layer(lintLayer{index: i, group: group})
+	}
+	for _, key := range l.keys {
+		l.frames(key)
+		if key.body {
+			l.bodyAnchors(key)
+		} else {
+			l.pairs(key)
+		}
+	}
+	slices.SortStableFunc(l.issues, func(a, b lintIssue) int { return a.index - b.index })
+	errs := make(Errors, 0, len(l.issues))
+	for _, issue := range l.issues {
+		errs = append(errs, issue.err)
+	}
+	return errs
+}
+
+// layer checks a layer on its own and files it under its key
+func (l *linter) layer(layer lintLayer) {
+	group := layer.group
+	if len(svgdoc.GetPathsInGroup(group)) == 0 {
+		l.report(layer, "", ErrNoPath)
+		return
+	}
+	name, frame, err := parseLabel(group.Label)
+	if err != nil {
+		l.report(layer, "", err)
+		return
+	}
+	if strings.Contains(name, "@") {
+		l.report(layer, "", ErrTypedLabel)
+		return
+	}
+	layer.frame = frame
+	l.colors(layer)
+	anchors := layerAnchors(group, true)
+	key := lintKey{name: name}
+
+	if isBody(group) {
+		key.body = true
+		layer.anchors = make(map[pet.BodypartType]int)
+		sides := make(map[pet.BodypartType][]pet.Side)
+		for _, a := range anchors {
+			t, side := pet.ParseAnchorLabel(a.label)
+			if !l.types.Known(t) {
+				l.report(layer, a.id, fmt.Errorf("%w %q", ErrUnknownType, a.label))
+				continue
+			}
+			if side != pet.SideNone && slices.Contains(sides[t], side) {
+				l.report(layer, a.id, fmt.Errorf("%w: two %s anchors on the %s", ErrDuplicateSide, t, side))
+			}
+			sides[t] = append(sides[t], side)
+			layer.anchors[t]++
+		}
+	} else {
+		if len(anchors) == 0 {
+			l.report(layer, "", ErrNoAnchor)
+			return
+		}
+		root := anchors[0]
+		if !root.top {
+			l.report(layer, root.id, ErrNestedAnchor)
+			return
+		}
+		for _, a := range anchors[1:] {
+			l.report(layer, a.id, ErrManyAnchors)
+		}
+		key.typ = pet.BodypartType(root.label)
+		if !l.types.Known(key.typ) {
+			l.report(layer, root.id, fmt.Errorf("%w %q", ErrUnknownType, root.label))
+			return
+		}
+	}
+
+	if _, ok := l.layers[key]; !ok {
+		l.keys = append(l.keys, key)
+	}
+	l.layers[key] = append(l.layers[key], layer)
+}
+
+// colors checks every fill and stroke of the layer has a palette slot
+func (l *linter) colors(layer lintLayer) {
+	for _, path := range svgdoc.GetPathsInGroup(layer.group) {
+		style := path.ParseStyle()
+		for _, c := range []struct{ name, color string }{{"fill", style.Fill}, {"stroke", style.Stroke}} {
+			if _, ok := l.opts.Colors.Slot(c.color); ok || c.color == "none" {
+				continue
+			}
+			err := fmt.Errorf("%s: %w %s", c.name, ErrUnmappedColor, c.color)
+			if l.opts.StrictColors {
+				l.report(layer, path.ID, err)
+			} else {
+				l.warn(layer, path.ID, err)
+			}
+		}
+	}
+}
+
+// frames checks the frames of a body or a bodypart are numbered from 0 and
+// follow each other
+func (l *linter) frames(key lintKey) {
+	layers := l.layers[key]
+	slices.SortStableFunc(layers, func(a, b lintLayer) int { return a.frame - b.frame })
+	if layers[0].frame != 0 {
+		l.report(layers[0], "", ErrNoFirstFrame)
+	}
+	for i := 1; i < len(layers); i++ {
+		previous, frame := layers[i-1].frame, layers[i].frame
+		switch {
+		case frame == previous:
+			l.report(layers[i], "", fmt.Errorf("%w %d", ErrDuplicateFrame, frame))
+		case frame == previous+2:
+			l.report(layers[i], "", fmt.Errorf("%w %d", ErrMissingFrame, previous+1))
+		case frame > previous+2:
+			l.report(layers[i], "", fmt.Errorf("%w %d to %d", ErrMissingFrame, previous+1, frame-1))
+		}
+	}
+}
+
+// frameCount is the number of distinct frames of a key
+func (l *linter) frameCount(key lintKey) int {
+	count := 0
+	for i, layer := range l.layers[key] {
+		if i == 0 || layer.frame != l.layers[key][i-1].frame {
+			count++
+		}
+	}
+	return count
+}
+
+// pairs checks an arm or a leg has a matching bodypart of the other side,
+// with as many frames
+func (l *linter) pairs(key lintKey) {
+	other, ok := l.types.Pair(key.typ)
+	if !ok {
+		return
+	}
+	otherKey := lintKey{typ: other, name: key.name}
+	if _, exists := l.layers[otherKey]; !exists {
+		l.report(l.layers[key][0], "", fmt.Errorf("%w: no %s %q", ErrUnpaired, other, key.name))
+		return
+	}
+	// reported once, on the first side
+	if l.types.Order(key.typ) > l.types.Order(other) {
+		return
+	}
+	if n, m := l.frameCount(key), l.frameCount(otherKey); n != m {
+		l.report(l.layers[key][0], "", fmt.Errorf("%w: %d %s frames, %d %s frames", ErrUnpaired, n, key.typ, m, other))
+	}
+}
+
+// bodyAnchors checks every frame of a body anchors the same bodyparts as its
+// first frame, arms and legs on both sides. The known types the body has no
+// anchor for are warned about once, bodies may do without some bodyparts.
+func (l *linter) bodyAnchors(key lintKey) {
+	layers := l.layers[key]
+	first := layers[0]
+	missing := make([]string, 0)
+	for _, info := range l.types.Types() {
+		if first.anchors[info.Type] == 0 {
+			missing = append(missing, string(info.Type))
+		}
+	}
+	if len(missing) > 0 {
+		l.warn(first, "", fmt.Errorf("%w %s", ErrMissingAnchor, strings.Join(missing, ", ")))
+	}
+	for _, layer := range layers {
+		types := make([]pet.BodypartType, 0)
+		for t := range first.anchors {
+			types = append(types, t)
+		}
+		for t := range layer.anchors {
+			if _, ok := first.anchors[t]; !ok {
+				types = append(types, t)
+			}
+		}
+		slices.SortFunc(types, l.types.Compare)
+		for _, t := range types {
+			if n := layer.anchors[t]; n != first.anchors[t] {
+				l.report(layer, "", fmt.Errorf("%w: %d %s anchors, frame %d has %d", ErrAnchorMismatch, n, t, first.frame, first.anchors[t]))
+			}
+			if other, ok := l.types.Pair(t); ok && (l.types.Order(t) < l.types.Order(other) || layer.anchors[other] == 0) && layer.anchors[t] != layer.anchors[other] {
+				l.report(layer, "", fmt.Errorf("%w: %d %s anchors, %d %s anchors", ErrUnpaired, layer.anchors[t], t, layer.anchors[other], other))
+			}
+		}
+	}
+}
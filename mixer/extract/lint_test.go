@@ -0,0 +1,136 @@
+package extract
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"tama/pet"
+	"tama/svgdoc"
+)
+
+func lintPart(id, label, anchor string) svgdoc.Group {
+	return svgdoc.Group{ID: id, Label: label, Paths: []svgdoc.Path{{ID: id + "-path", D: "M 0 0 L 10 10"}}, Ellipses: []svgdoc.Ellipse{{ID: id + "-anchor", Label: anchor}}}
+}
+
+func lintBody(id, label string, anchors ...string) svgdoc.Group {
+	group := svgdoc.Group{ID: id, Label: label, Paths: []svgdoc.Path{{ID: id + "-path", Label: "body", D: "M 0 0 L 10 0 L 10 10 Z"}}}
+	for _, a := range anchors {
+		group.Circles = append(group.Circles, svgdoc.Circle{ID: id + "-" + a, Label: a})
+	}
+	return group
+}
+
+func TestLintValid(t *testing.T) {
+	sheet := svgdoc.SVG{Groups: []svgdoc.Group{
+		lintPart("layer1", "stick-0", "arm1"),
+		lintPart("layer2", "stick-1", "arm1"),
+		lintPart("layer3", "stick-1", "arm2"),
+		lintPart("layer4", "stick-0", "arm2"),
+		lintPart("layer5", "roundeye-0", "eye"),
+		lintBody("layer6", "ball-0", "eye", "eye", "arm1", "arm2"),
+		lintBody("layer7", "ball-1", "arm2", "eye.right", "arm1", "eye"),
+	}}
+	// the ball anchors every type
+	types, err := pet.NewRegistry([]pet.TypeInfo{pet.DefaultTypes[0], pet.DefaultTypes[2], pet.DefaultTypes[3]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := testOptions
+	opts.Types = types
+	if errs := Lint(sheet, opts); len(errs) != 0 {
+		t.Errorf("expected no problem, got %v", errs)
+	}
+}
+
+func TestLintMissingAnchor(t *testing.T) {
+	sheet := svgdoc.SVG{Groups: []svgdoc.Group{
+		lintBody("layer1", "ball-0", "mouth"),
+		lintBody("layer2", "ball-1", "mouth"),
+	}}
+	types, err := pet.NewRegistry([]pet.TypeInfo{{Type: pet.BodypartType_Eye, Inside: true}, {Type: pet.BodypartType_Mouth, Inside: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := testOptions
+	opts.Types = types
+	errs := Lint(sheet, opts)
+	if len(errs) != 1 || errs[0].LayerID != "layer1" || !errs[0].Warning || !errors.Is(errs[0], ErrMissingAnchor) || !strings.HasSuffix(errs[0].Error(), " eye") {
+		t.Errorf("expected a warning about the eye of the first frame, got %v", errs)
+	}
+}
+
+func TestLintColors(t *testing.T) {
+	part := lintPart("layer1", "dot-0", "eye")
+	part.Paths[0].Style = "fill:#ff0000;stroke:#000000"
+	sheet := svgdoc.SVG{Groups: []svgdoc.Group{part}}
+
+	errs := Lint(sheet, testOptions)
+	if len(errs) != 1 || !errs[0].Warning || errs[0].Element != "layer1-path" || !errors.Is(errs[0], ErrUnmappedColor) {
+		t.Fatalf("expected a warning about red, got %v", errs)
+	}
+	if len(errs.Failures()) != 0 {
+		t.Errorf("warnings are not failures")
+	}
+	strict := testOptions
+	strict.StrictColors = true
+	if errs := Lint(sheet, strict); len(errs.Failures()) != 1 {
+		t.Errorf("expected red to fail, got %v", errs)
+	}
+}
+
+func TestLintReportsEveryProblem(t *testing.T) {
+	twoAnchors := lintPart("layer5", "wing-0", "arm1")
+	twoAnchors.Circles = []svgdoc.Circle{{ID: "extra", Label: "arm1"}}
+	nested := svgdoc.Group{ID: "layer6", Label: "dot-0", Groups: []svgdoc.Group{lintPart("sub", "", "eye")}}
+
+	sheet := svgdoc.SVG{Groups: []svgdoc.Group{
+		lintPart("layer1", "mouth@smile-0", "mouth"),
+		lintPart("layer2", "smile", "mouth"),
+		{ID: "layer3", Label: "line-0", Paths: []svgdoc.Path{{D: "M 0 0 L 1 1"}}},
+		lintPart("layer4", "horn-0", "horn"),
+		twoAnchors,
+		nested,
+		lintPart("layer7", "noodle-1", "leg1"),
+		lintPart("layer8", "paddle-0", "arm1"),
+		lintPart("layer9", "paddle-3", "arm1"),
+		lintPart("layer10", "paddle-3", "arm1"),
+		lintPart("layer11", "paddle-0", "arm2"),
+		lintBody("layer12", "mush-0", "eye.left", "eye.left", "leg1", "leg2", "wings"),
+		lintBody("layer13", "mush-1", "eye", "eye.right", "leg1"),
+		{ID: "layer14", Label: "empty-0"},
+	}}
+	want := []struct {
+		layerID string
+		element string
+		err     error
+	}{
+		{"layer1", "", ErrTypedLabel},
+		{"layer2", "", ErrBadLabel},
+		{"layer3", "", ErrNoAnchor},
+		{"layer4", "layer4-anchor", ErrUnknownType},
+		{"layer5", "extra", ErrManyAnchors},
+		{"layer5", "", ErrUnpaired},
+		{"layer6", "sub-anchor", ErrNestedAnchor},
+		{"layer7", "", ErrNoFirstFrame},
+		{"layer7", "", ErrUnpaired},
+		{"layer8", "", ErrUnpaired},
+		{"layer9", "", ErrMissingFrame},
+		{"layer10", "", ErrDuplicateFrame},
+		{"layer12", "layer12-eye.left", ErrDuplicateSide},
+		{"layer12", "layer12-wings", ErrUnknownType},
+		{"layer12", "", ErrMissingAnchor},
+		{"layer13", "", ErrUnpaired},
+		{"layer13", "", ErrAnchorMismatch},
+		{"layer14", "", ErrNoPath},
+	}
+	errs := Lint(sheet, testOptions)
+	if len(errs) != len(want) {
+		t.Fatalf("expected %d problems, got %d:\n%v", len(want), len(errs), errs)
+	}
+	for i, w := range want {
+		if errs[i].LayerID != w.layerID || errs[i].Element != w.element || !errors.Is(errs[i], w.err) {
+			t.Errorf("problem %d: got %v, expected layer %q element %q: %v", i, errs[i], w.layerID, w.element, w.err)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package extract
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"tama/geom"
+	"tama/pet"
+)
+
+// mirrorSamples is the number of points measured on every curve of an
+// outline when comparing a bodypart with its mirror
+const mirrorSamples = 16
+
+// MirrorBodyPart flips a normalized bodypart across its anchor axis, the
+// vertical line through its root anchor, and gives it the type t
+func MirrorBodyPart(part pet.BodyPart, t pet.BodypartType) (pet.BodyPart, error) {
+	commands, err := geom.ParseD(part.Path)
+	if err != nil {
+		return pet.BodyPart{}, err
+	}
+	m := geom.MirrorMatrix(90)
+	commands = m.TransformCommands(commands)
+	bb := part.BoundingBox
+	part.Path = geom.CompileD(commands)
+	part.Paths = slices.Clone(part.Paths)
+	for i, sub := range part.Paths {
+		commands, err := geom.ParseD(sub.Path)
+		if err != nil {
+			return pet.BodyPart{}, err
+		}
+		part.Paths[i].Path = geom.CompileD(m.TransformCommands(commands))
+	}
+	part.Type = t
+	part.BoundingBox = geom.Rect{
+		TopLeft:     geom.Point{X: -bb.BottomRight.X, Y: bb.TopLeft.Y},
+		BottomRight: geom.Point{X: -bb.TopLeft.X, Y: bb.BottomRight.Y},
+	}
+	return part, nil
+}
+
+// Divergence is the largest distance from a point of one outline to the
+// other outline, 0 when both draw the same shape
+func Divergence(a, b pet.BodyPart) (float64, error) {
+	beziersA, err := outline(a)
+	if err != nil {
+		return 0, err
+	}
+	beziersB, err := outline(b)
+	if err != nil {
+		return 0, err
+	}
+	return math.Max(farthest(beziersA, beziersB), farthest(beziersB, beziersA)), nil
+}
+
+func outline(part pet.BodyPart) ([]geom.Bezier, error) {
+	commands, err := geom.ParseD(part.Path)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s-%d: %w", part.Type, part.Name, part.Frame, err)
+	}
+	return geom.GetBeziersFromCommands(commands), nil
+}
+
+// farthest is the largest distance from a point sampled on from to the curves
+// of to
+func farthest(from, to []geom.Bezier) float64 {
+	distance := 0.0
+	for _, bz := range from {
+		for i := 0; i <= mirrorSamples; i++ {
+			p := geom.GetPointFromBezier(bz, float64(i)/mirrorSamples)
+			_, projection, ok := geom.ProjectOnBeziers(to, p)
+			if !ok {
+				return math.Inf(1)
+			}
+			distance = math.Max(distance, projection.Distance)
+		}
+	}
+	return distance
+}
+
+// MirrorReport is a hand-drawn bodypart too far from the mirror of its pair
+type MirrorReport struct {
+	Type pet.BodypartType
+	// Pair is the type of the bodypart that was mirrored
+	Pair     pet.BodypartType
+	Name     string
+	Frame    int
+	Distance float64
+}
+
+func (r MirrorReport) String() string {
+	return fmt.Sprintf("%s %s-%d is %.2f away from the mirrored %s", r.Type, r.Name, r.Frame, r.Distance, r.Pair)
+}
+
+// MirrorPairs derives the second side of paired bodyparts, arm2 and leg2,
+// from the first when a name only has the first one. Pairs drawn by hand are
+// kept, the frames farther than tolerance from the mirror of their first
+// side are reported.
+func MirrorPairs(bodyparts []pet.BodyPart, types pet.Registry, tolerance float64) ([]pet.BodyPart, []MirrorReport, error) {
+	type key struct {
+		typ   pet.BodypartType
+		name  string
+		frame int
+	}
+	type side struct {
+		typ  pet.BodypartType
+		name string
+	}
+	frames := make(map[key]pet.BodyPart, len(bodyparts))
+	drawn := make(map[side]bool)
+	for _, part := range bodyparts {
+		frames[key{part.Type, part.Name, part.Frame}] = part
+		drawn[side{part.Type, part.Name}] = true
+	}
+
+	result := slices.Clone(bodyparts)
+	reports := make([]MirrorReport, 0)
+	for _, part := range bodyparts {
+		other, ok := types.Pair(part.Type)
+		if !ok || types.Order(other) < types.Order(part.Type) {
+			continue
+		}
+		mirrored, err := MirrorBodyPart(part, other)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s %s-%d: %w", part.Type, part.Name, part.Frame, err)
+		}
+		// a name drawn for both sides is never completed by mirroring
+		if !drawn[side{other, part.Name}] {
+			result = append(result, mirrored)
+			continue
+		}
+		pair, ok := frames[key{other, part.Name, part.Frame}]
+		if !ok {
+			continue
+		}
+		distance, err := Divergence(mirrored, pair)
+		if err != nil {
+			return nil, nil, err
+		}
+		if distance > tolerance {
+			reports = append(reports, MirrorReport{Type: other, Pair: part.Type, Name: part.Name, Frame: part.Frame, Distance: distance})
+		}
+	}
+	slices.SortFunc(reports, func(a, b MirrorReport) int {
+		if order := types.Compare(a.Type, b.Type); order != 0 {
+			return order
+		}
+		if order := strings.Compare(a.Name, b.Name); order != 0 {
+			return order
+		}
+		return a.Frame - b.Frame
+	})
+	return result, reports, nil
+}
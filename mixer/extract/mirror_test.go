@@ -0,0 +1,74 @@
+package extract
+
+import (
+	"math"
+	"testing"
+
+	"tama/geom"
+	"tama/pet"
+)
+
+func TestMirrorBodyPart(t *testing.T) {
+	part := pet.BodyPart{
+		Path:        "M 0 0 L 2 -3 L 3 -1",
+		Paths:       []pet.SubPath{{Path: "M 0 0 L 2 -3 L 3 -1", Fill: pet.SlotFill2}},
+		Type:        pet.BodypartType_Arm1,
+		Name:        "hook",
+		BoundingBox: geom.Rect{TopLeft: geom.Point{X: 0, Y: -3}, BottomRight: geom.Point{X: 3, Y: 0}},
+	}
+	mirrored, err := MirrorBodyPart(part, pet.BodypartType_Arm2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mirrored.Type != pet.BodypartType_Arm2 || mirrored.Name != "hook" {
+		t.Errorf("got %s %s expected arm2 hook", mirrored.Type, mirrored.Name)
+	}
+	want := geom.Rect{TopLeft: geom.Point{X: -3, Y: -3}, BottomRight: geom.Point{X: 0, Y: 0}}
+	if mirrored.BoundingBox != want {
+		t.Errorf("got box %v expected %v", mirrored.BoundingBox, want)
+	}
+	if len(mirrored.Paths) != 1 || mirrored.Paths[0].Path != mirrored.Path || part.Paths[0].Path == mirrored.Path {
+		t.Errorf("sub-paths are not mirrored along: %v", mirrored.Paths)
+	}
+	commands, err := geom.ParseD(mirrored.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	beziers := geom.GetBeziersFromCommands(commands)
+	if len(beziers) != 2 || math.Abs(beziers[0].P3.X+2) > 1e-9 || math.Abs(beziers[1].P3.X+3) > 1e-9 {
+		t.Errorf("got %v expected the points on the left of the anchor", beziers)
+	}
+}
+
+func TestMirrorPairs(t *testing.T) {
+	bodyparts := []pet.BodyPart{
+		{Path: "M 0 0 L 2 -3", Type: pet.BodypartType_Arm1, Name: "hook", Frame: 0},
+		{Path: "M 0 0 L 2 -4", Type: pet.BodypartType_Arm1, Name: "hook", Frame: 1},
+		{Path: "M 0 0 L 0 -3", Type: pet.BodypartType_Leg1, Name: "stick", Frame: 0},
+		{Path: "M 0 0 L 0.1 -3", Type: pet.BodypartType_Leg2, Name: "stick", Frame: 0},
+		{Path: "M 0 0 L 1 -2", Type: pet.BodypartType_Leg1, Name: "bent", Frame: 0},
+		{Path: "M 0 0 L 1 -2", Type: pet.BodypartType_Leg2, Name: "bent", Frame: 0},
+		{Path: "M 0 0 L 1 1", Type: pet.BodypartType_Eye, Name: "dot", Frame: 0},
+	}
+	result, reports, err := MirrorPairs(bodyparts, pet.DefaultRegistry, 0.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// both hook frames get an arm2, the hand-drawn legs are kept
+	if len(result) != len(bodyparts)+2 {
+		t.Fatalf("got %d bodyparts expected %d: %v", len(result), len(bodyparts)+2, result)
+	}
+	for i, added := range result[len(bodyparts):] {
+		if added.Type != pet.BodypartType_Arm2 || added.Name != "hook" || added.Frame != i {
+			t.Errorf("got %s %s-%d expected arm2 hook-%d", added.Type, added.Name, added.Frame, i)
+		}
+	}
+	// the stick is symmetric enough, the bent leg2 should lean the other way
+	if len(reports) != 1 || reports[0].Name != "bent" || reports[0].Type != pet.BodypartType_Leg2 {
+		t.Fatalf("got reports %v expected bent leg2", reports)
+	}
+	// (-1, -2) is closest to (0.6, -1.2) on the hand-drawn leg
+	if math.Abs(reports[0].Distance-math.Sqrt(3.2)) > 1e-6 {
+		t.Errorf("got distance %f expected %f", reports[0].Distance, math.Sqrt(3.2))
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"tama/compose"
+	"tama/pet"
+	"tama/raster"
+	"tama/svgdoc"
+)
+
+type generateOptions struct {
+	options
+	filter  compose.Filter
+	format  string
+	scale   float64
+	palette compose.Palette
+}
+
+// runGenerate writes every body and bodypart combination as an SVG or a PNG,
+// named as described in the README
+func runGenerate(args []string, stdout, stderr io.Writer) int {
+	opts, err := parseGenerateFlags(args, stderr)
+	if err == flag.ErrHelp {
+		return 0
+	}
+	if err != nil {
+		return 2
+	}
+
+	bodies, bodyparts, ok := extractAll(opts.options, stdout, stderr)
+	if !ok {
+		return 1
+	}
+
+	generatedDir := filepath.Join(opts.outDir, "generated")
+	for _, combination := range compose.Combinations(bodies, bodyparts, opts.filter) {
+		name := combination.Name()
+		composition, err := combination.Compose(opts.types)
+		if err != nil {
+			fmt.Fprintf(stderr, "mixer: generating %s: %v\n", name, err)
+			return 1
+		}
+		if opts.verbose || opts.dryRun {
+			fmt.Fprintln(stdout, filepath.Join(generatedDir, name+"."+opts.format))
+		}
+		if opts.dryRun {
+			continue
+		}
+		if opts.format == "png" {
+			err = savePNG(generatedDir, name, composition, opts.scale, opts.palette)
+		} else {
+			err = svgdoc.Save(generatedDir, name, composition.SVG(opts.palette))
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "mixer: writing %s: %v\n", name, err)
+			return 1
+		}
+	}
+	return 0
+}
+
+// savePNG writes the rasterized composition to dir/name.png
+func savePNG(dir, name string, composition compose.Composition, scale float64, palette compose.Palette) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	file, err := os.Create(filepath.Join(dir, name+".png"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return raster.WritePNG(file, composition, scale, palette)
+}
+
+func parseGenerateFlags(args []string, stderr io.Writer) (generateOptions, error) {
+	var opts generateOptions
+	var palette string
+	fs := flag.NewFlagSet("mixer generate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: mixer generate [flags] [sheet.svg ...]\n\n")
+		fmt.Fprintf(stderr, "Writes every body frame with every bodypart frame pinned on it as SVG or PNG (default sheet %s).\n\n", defaultInput)
+		fs.PrintDefaults()
+	}
+	fs.StringVar(&opts.outDir, "out", "out", "output root, combinations are written in the generated sub directory")
+	addExtractFlags(fs, &opts.options, "list the files that would be written without writing them")
+	filter := addFilterFlags(fs, "generate")
+	fs.StringVar(&opts.format, "format", "svg", "output format, svg or png")
+	fs.Float64Var(&opts.scale, "scale", 8, "png pixels per sheet unit")
+	fs.StringVar(&palette, "palette", "", paletteUsage)
+	err := fs.Parse(args)
+	if err != nil {
+		return opts, err
+	}
+	if opts.format != "svg" && opts.format != "png" {
+		fmt.Fprintf(stderr, "mixer generate: unknown format %q\n", opts.format)
+		return opts, errBadFlag
+	}
+	if opts.scale <= 0 {
+		fmt.Fprintf(stderr, "mixer generate: scale must be positive\n")
+		return opts, errBadFlag
+	}
+	if err := useExtractFlags(fs, &opts.options, stderr); err != nil {
+		return opts, err
+	}
+	if opts.palette, err = parsePalette(palette, opts.palettes); err != nil {
+		fmt.Fprintf(stderr, "mixer generate: palette: %v\n", err)
+		return opts, errBadFlag
+	}
+	opts.filter = filter.filter()
+	return opts, nil
+}
+
+// filterFlags are the comma separated -bodies, -types and -parts flags of the
+// commands writing combinations
+type filterFlags struct {
+	bodies, types, parts string
+}
+
+// addFilterFlags registers the flags restricting the combinations, verb is
+// what the command does with them
+func addFilterFlags(fs *flag.FlagSet, verb string) *filterFlags {
+	var f filterFlags
+	fs.StringVar(&f.bodies, "bodies", "", "comma separated body names to "+verb+", all by default")
+	fs.StringVar(&f.types, "types", "", "comma separated bodypart types to "+verb+", all by default")
+	fs.StringVar(&f.parts, "parts", "", "comma separated bodypart names to "+verb+", all by default")
+	return &f
+}
+
+// filter is what the parsed flags allow
+func (f *filterFlags) filter() compose.Filter {
+	filter := compose.Filter{Bodies: splitList(f.bodies), Parts: splitList(f.parts)}
+	for _, t := range splitList(f.types) {
+		filter.Types = append(filter.Types, pet.BodypartType(t))
+	}
+	return filter
+}
+
+// splitList splits a comma separated flag value, empty items are dropped
+func splitList(s string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+const paletteUsage = "palette name from -palettes, or comma separated stroke, fill1, fill2, accent and highlight colors (the last two are optional), the renderer palette by default"
+
+// parsePalette reads the -palette flag: the name of a palette of the
+// manifest, or its colors in slot order, empty is the renderer palette
+func parsePalette(s string, palettes map[string]compose.Palette) (compose.Palette, error) {
+	if s == "" {
+		return compose.PetPalette, nil
+	}
+	if palette, ok := palettes[s]; ok {
+		return palette, nil
+	}
+	colors := splitList(s)
+	if len(colors) < 3 || len(colors) > 5 {
+		return compose.Palette{}, fmt.Errorf("unknown palette or 3 to 5 colors expected, got %q", s)
+	}
+	for _, c := range colors {
+		if _, err := raster.ParseColor(c); err != nil {
+			return compose.Palette{}, err
+		}
+	}
+	// accents and highlights are left as in the renderer palette
+	palette := compose.PetPalette
+	palette.Stroke, palette.BodyFill, palette.PartsFill = colors[0], colors[1], colors[2]
+	if len(colors) > 3 {
+		palette.Accent = colors[3]
+	}
+	if len(colors) > 4 {
+		palette.Highlight = colors[4]
+	}
+	return palette, nil
+}
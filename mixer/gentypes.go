@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"tama/pet"
+	"tama/tsgen"
+)
+
+type genTypesOptions struct {
+	options
+	file string
+}
+
+// runGenTypes writes the TypeScript declarations of the exported bodies and
+// bodyparts, with the names of those found in the sheets
+func runGenTypes(args []string, stdout, stderr io.Writer) int {
+	opts, err := parseGenTypesFlags(args, stderr)
+	if err == flag.ErrHelp {
+		return 0
+	}
+	if err != nil {
+		return 2
+	}
+
+	bodies, bodyparts, ok := extractAll(opts.options, stdout, stderr)
+	if !ok {
+		return 1
+	}
+
+	var buf bytes.Buffer
+	if err := writeTypes(&buf, opts.inputs, bodies, bodyparts, opts.types); err != nil {
+		fmt.Fprintf(stderr, "mixer: %v\n", err)
+		return 1
+	}
+	if opts.verbose || opts.dryRun {
+		fmt.Fprintln(stdout, opts.file)
+	}
+	if opts.dryRun {
+		return 0
+	}
+	if err := os.MkdirAll(filepath.Dir(opts.file), 0755); err != nil {
+		fmt.Fprintf(stderr, "mixer: writing types: %v\n", err)
+		return 1
+	}
+	if err := os.WriteFile(opts.file, buf.Bytes(), 0644); err != nil {
+		fmt.Fprintf(stderr, "mixer: writing types: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// writeTypes declares Body, BodyPart and the types they are made of, anchor
+// and bodypart types and the palette slots of the sub-paths as unions of the
+// known ones, and BodyName and PartName as the names found in the sheets
+func writeTypes(w io.Writer, inputs []string, bodies []pet.Body, bodyparts []pet.BodyPart, registry pet.Registry) error {
+	types := make([]string, 0)
+	for _, info := range registry.Types() {
+		types = append(types, string(info.Type))
+	}
+	bodyNames := make([]string, 0)
+	for _, body := range bodies {
+		bodyNames = append(bodyNames, body.Name)
+	}
+	partNames := make([]string, 0)
+	for _, part := range bodyparts {
+		partNames = append(partNames, part.Name)
+		// types only the sheets know about are drawn all the same
+		if !slices.Contains(types, string(part.Type)) {
+			types = append(types, string(part.Type))
+		}
+	}
+	slices.Sort(bodyNames)
+	slices.Sort(partNames)
+
+	sides := []string{string(pet.SideLeft), string(pet.SideRight)}
+
+	g := tsgen.New()
+	steps := []func() error{
+		func() error { return g.Enum(reflect.TypeFor[pet.BodypartType](), types) },
+		func() error { return g.Enum(reflect.TypeFor[pet.Side](), sides) },
+		func() error { return g.Union("PaletteSlot", pet.Slots) },
+		func() error { return g.Override(reflect.TypeFor[pet.SubPath](), "Fill", "PaletteSlot") },
+		func() error { return g.Override(reflect.TypeFor[pet.SubPath](), "Stroke", "PaletteSlot") },
+		func() error { return g.Union("BodyName", slices.Compact(bodyNames)) },
+		func() error { return g.Union("PartName", slices.Compact(partNames)) },
+		func() error { return g.Add(reflect.TypeFor[pet.Body]()) },
+		func() error { return g.Add(reflect.TypeFor[pet.BodyPart]()) },
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(w, "// Code generated by mixer gen-types from %s. DO NOT EDIT.\n\n", strings.Join(inputs, ", "))
+	_, err := g.WriteTo(w)
+	return err
+}
+
+func parseGenTypesFlags(args []string, stderr io.Writer) (genTypesOptions, error) {
+	var opts genTypesOptions
+	fs := flag.NewFlagSet("mixer gen-types", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: mixer gen-types [flags] [sheet.svg ...]\n\n")
+		fmt.Fprintf(stderr, "Writes the TypeScript declarations of the exported frames and the body and bodypart names of the sheets (default %s).\n\n", defaultInput)
+		fs.PrintDefaults()
+	}
+	fs.StringVar(&opts.file, "o", filepath.Join("out", "mixer.d.ts"), "declaration file to write")
+	addExtractFlags(fs, &opts.options, "extract and print the file that would be written without writing it")
+	if err := fs.Parse(args); err != nil {
+		return opts, err
+	}
+	return opts, useExtractFlags(fs, &opts.options, stderr)
+}
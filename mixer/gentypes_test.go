@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"tama/pet"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files of testdata")
+
+func TestWriteTypes(t *testing.T) {
+	bodies := []pet.Body{{Name: "mush"}, {Name: "ball"}, {Name: "ball", Frame: 1}}
+	bodyparts := []pet.BodyPart{
+		{Type: pet.BodypartType_Eye, Name: "roundeye"},
+		{Type: "antenna", Name: "stick"},
+	}
+	var buf bytes.Buffer
+	if err := writeTypes(&buf, []string{"parts.svg"}, bodies, bodyparts, pet.DefaultRegistry); err != nil {
+		t.Fatal(err)
+	}
+
+	golden := filepath.Join("testdata", "mixer.d.ts")
+	if *update {
+		if err := os.WriteFile(golden, buf.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("got\n%s\nexpected %s, run go test -update to rewrite it", buf.String(), golden)
+	}
+}
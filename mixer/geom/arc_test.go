@@ -0,0 +1,47 @@
+package geom
+
+import (
+	"math"
+	"testing"
+)
+
+func TestArcToBeziersFollowsEllipse(t *testing.T) {
+	// a quarter of the unit circle, around the origin
+	beziers := ArcToBeziers(Point{X: 1, Y: 0}, 1, 1, 0, 0, 1, 0, 1)
+	if len(beziers) != 1 {
+		t.Fatalf("got %d segments expected 1", len(beziers))
+	}
+	for _, at := range []float64{0.25, 0.5, 0.75} {
+		p := GetPointFromBezier(beziers[0], at)
+		if r := math.Hypot(p.X, p.Y); math.Abs(r-1) > 0.01 {
+			t.Errorf("point at %v is %v, %v from the center expected 1", at, p, r)
+		}
+	}
+
+	// a half circle is exactly two quarters
+	if beziers := ArcToBeziers(Point{X: 1, Y: 0}, 1, 1, 0, 0, 1, -1, 0); len(beziers) != 2 {
+		t.Errorf("got %d segments expected 2", len(beziers))
+	}
+}
+
+func TestArcToBeziersScalesRadii(t *testing.T) {
+	// the radii can't reach (10, 0), they are scaled up to a half circle
+	tests := []struct {
+		rx, ry, rotation float64
+	}{
+		{1, 1, 0},
+		{1, 3, 30},
+		{0.5, 0.2, 90},
+	}
+	for _, tc := range tests {
+		beziers := ArcToBeziers(Point{X: 0, Y: 0}, tc.rx, tc.ry, tc.rotation, 0, 1, 10, 0)
+		if len(beziers) == 0 {
+			t.Errorf("%v: got no segment", tc)
+			continue
+		}
+		first, last := beziers[0].P0, beziers[len(beziers)-1].P3
+		if first.Sub(Point{X: 0, Y: 0}).Length() > 1e-9 || last.Sub(Point{X: 10, Y: 0}).Length() > 1e-9 {
+			t.Errorf("%v: got an arc from %v to %v expected from (0, 0) to (10, 0)", tc, first, last)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package geom
+
+import (
+	"math"
+	"strconv"
+)
+
+type Bezier struct {
+	P0 Point
+	P1 Point
+	P2 Point
+	P3 Point
+}
+
+func BeziersToD(beziers []Bezier) string {
+	result := ""
+	for _, b := range beziers {
+		result += " M " + strconv.FormatFloat(b.P0.X, 'f', -1, 64) + " " + strconv.FormatFloat(b.P0.Y, 'f', -1, 64)
+		points := []Point{b.P1, b.P2, b.P3}
+		result += " C "
+		for _, p := range points {
+			result += strconv.FormatFloat(p.X, 'f', -1, 64) + " " + strconv.FormatFloat(p.Y, 'f', -1, 64) + " "
+		}
+	}
+	return result
+}
+
+func GetPointFromBezier(bezier Bezier, t float64) Point {
+	x := math.Pow(1-t, 3)*bezier.P0.X + 3*math.Pow(1-t, 2)*t*bezier.P1.X + 3*(1-t)*t*t*bezier.P2.X + math.Pow(t, 3)*bezier.P3.X
+	y := math.Pow(1-t, 3)*bezier.P0.Y + 3*math.Pow(1-t, 2)*t*bezier.P1.Y + 3*(1-t)*t*t*bezier.P2.Y + math.Pow(t, 3)*bezier.P3.Y
+	return Point{X: math.Round(x*100) / 100, Y: math.Round(y*100) / 100}
+}
+
+// GetRotationFromBezierRadian returns the direction of the tangent at t, in
+// ]-pi, pi]
+func GetRotationFromBezierRadian(bezier Bezier, t float64) float64 {
+	d := bezier.Derivative(t)
+	return math.Atan2(d.Y, d.X)
+}
+
+func ArcToBeziers(current Point, rx, ry, xAxisRotation float64, largeArcFlag, sweepFlag int, x, y float64) []Bezier {
+	// Convertir rotation en radians
+	phi := xAxisRotation * math.Pi / 180.0
+
+	// Point final
+	P1 := Point{X: x, Y: y}
+
+	// Déplacement si start == end
+	if current.X == P1.X && current.Y == P1.Y {
+		return nil
+	}
+
+	// Correction des rayons si nécessaire
+	rx = math.Abs(rx)
+	ry = math.Abs(ry)
+	if rx == 0 || ry == 0 {
+		return []Bezier{{P0: current, P1: current, P2: P1, P3: P1}}
+	}
+
+	// Convertir coordonnées dans le repère de l'ellipse
+	dx2 := (current.X - P1.X) / 2.0
+	dy2 := (current.Y - P1.Y) / 2.0
+	x1p := math.Cos(phi)*dx2 + math.Sin(phi)*dy2
+	y1p := -math.Sin(phi)*dx2 + math.Cos(phi)*dy2
+
+	// Rayons trop petits pour relier les deux points : agrandis (SVG F.6.6)
+	if lambda := x1p*x1p/(rx*rx) + y1p*y1p/(ry*ry); lambda > 1 {
+		rx *= math.Sqrt(lambda)
+		ry *= math.Sqrt(lambda)
+	}
+
+	// Calcul du centre cx', cy'
+	rx2 := rx * rx
+	ry2 := ry * ry
+	x1p2 := x1p * x1p
+	y1p2 := y1p * y1p
+
+	var sign float64 = 1
+	if largeArcFlag == sweepFlag {
+		sign = -1
+	}
+
+	sq := ((rx2*ry2 - rx2*y1p2 - ry2*x1p2) / (rx2*y1p2 + ry2*x1p2))
+	if sq < 0 {
+		sq = 0
+	}
+	coef := sign * math.Sqrt(sq)
+	cxp := coef * (rx * y1p / ry)
+	cyp := coef * -(ry * x1p / rx)
+
+	// Centre dans le repère original
+	cx := math.Cos(phi)*cxp - math.Sin(phi)*cyp + (current.X+P1.X)/2
+	cy := math.Sin(phi)*cxp + math.Cos(phi)*cyp + (current.Y+P1.Y)/2
+
+	// Angles de début et de fin
+	theta1 := math.Atan2((y1p-cyp)/ry, (x1p-cxp)/rx)
+	deltaTheta := math.Atan2((-y1p-cyp)/ry, (-x1p-cxp)/rx) - theta1
+
+	if sweepFlag == 0 && deltaTheta > 0 {
+		deltaTheta -= 2 * math.Pi
+	} else if sweepFlag == 1 && deltaTheta < 0 {
+		deltaTheta += 2 * math.Pi
+	}
+
+	// Diviser en segments ≤ 90°
+	segments := int(math.Ceil(math.Abs(deltaTheta)/(math.Pi/2) - 1e-9))
+	delta := deltaTheta / float64(segments)
+
+	var beziers []Bezier
+	for i := 0; i < segments; i++ {
+		t1 := theta1 + float64(i)*delta
+		t2 := t1 + delta
+
+		// Points de Bézier pour ce segment
+		alpha := math.Sin(delta) * (math.Sqrt(4+3*math.Pow(math.Tan(delta/2), 2)) - 1) / 3
+
+		p0 := Point{
+			X: cx + rx*math.Cos(phi)*math.Cos(t1) - ry*math.Sin(phi)*math.Sin(t1),
+			Y: cy + rx*math.Sin(phi)*math.Cos(t1) + ry*math.Cos(phi)*math.Sin(t1),
+		}
+		p3 := Point{
+			X: cx + rx*math.Cos(phi)*math.Cos(t2) - ry*math.Sin(phi)*math.Sin(t2),
+			Y: cy + rx*math.Sin(phi)*math.Cos(t2) + ry*math.Cos(phi)*math.Sin(t2),
+		}
+		// les points de contrôle suivent la tangente de l'ellipse
+		d1 := Point{
+			X: -rx*math.Cos(phi)*math.Sin(t1) - ry*math.Sin(phi)*math.Cos(t1),
+			Y: -rx*math.Sin(phi)*math.Sin(t1) + ry*math.Cos(phi)*math.Cos(t1),
+		}
+		d2 := Point{
+			X: -rx*math.Cos(phi)*math.Sin(t2) - ry*math.Sin(phi)*math.Cos(t2),
+			Y: -rx*math.Sin(phi)*math.Sin(t2) + ry*math.Cos(phi)*math.Cos(t2),
+		}
+		p1 := Point{X: p0.X + alpha*d1.X, Y: p0.Y + alpha*d1.Y}
+		p2 := Point{X: p3.X - alpha*d2.X, Y: p3.Y - alpha*d2.Y}
+
+		beziers = append(beziers, Bezier{P0: p0, P1: p1, P2: p2, P3: p3})
+	}
+
+	return beziers
+}
+
+// lineBezier is the straight segment from p0 to p3 as a bezier
+func lineBezier(p0, p3 Point) Bezier {
+	return Bezier{P0: p0, P1: p0, P2: p3, P3: p3}
+}
+
+// Fonction principale
+func GetBeziersFromCommands(commands []Command) []Bezier {
+	results := make([]Bezier, 0)
+	current := Point{X: 0, Y: 0}
+	var zPoint *Point = nil
+
+	for _, command := range commands {
+		switch command.Type {
+		case "M", "m":
+			if command.Type == "m" {
+				current = current.Add(Point{X: command.Args[0], Y: command.Args[1]})
+			} else {
+				current = Point{X: command.Args[0], Y: command.Args[1]}
+			}
+			// Z goes back to the most recent moveto
+			zPoint = &Point{X: current.X, Y: current.Y}
+			// extra coordinate pairs are implicit linetos
+			for i := 2; i < len(command.Args); i += 2 {
+				next := Point{X: command.Args[i], Y: command.Args[i+1]}
+				if command.Type == "m" {
+					next = next.Add(current)
+				}
+				results = append(results, lineBezier(current, next))
+				current = next
+			}
+		case "L", "l":
+			for i := 0; i < len(command.Args); i += 2 {
+				next := Point{X: command.Args[i], Y: command.Args[i+1]}
+				if command.Type == "l" {
+					next = next.Add(current)
+				}
+				results = append(results, lineBezier(current, next))
+				current = next
+			}
+		case "H", "h":
+			for _, x := range command.Args {
+				next := Point{X: x, Y: current.Y}
+				if command.Type == "h" {
+					next.X += current.X
+				}
+				results = append(results, lineBezier(current, next))
+				current = next
+			}
+		case "V", "v":
+			for _, y := range command.Args {
+				next := Point{X: current.X, Y: y}
+				if command.Type == "v" {
+					next.Y += current.Y
+				}
+				results = append(results, lineBezier(current, next))
+				current = next
+			}
+		case "C", "c":
+			for i := 0; i < len(command.Args); i += 6 {
+				var b Bezier
+				b.P0 = current
+				if command.Type == "c" {
+					b.P1 = Point{X: current.X + command.Args[i], Y: current.Y + command.Args[i+1]}
+					b.P2 = Point{X: current.X + command.Args[i+2], Y: current.Y + command.Args[i+3]}
+					b.P3 = Point{X: current.X + command.Args[i+4], Y: current.Y + command.Args[i+5]}
+				} else {
+					b.P1 = Point{X: command.Args[i], Y: command.Args[i+1]}
+					b.P2 = Point{X: command.Args[i+2], Y: command.Args[i+3]}
+					b.P3 = Point{X: command.Args[i+4], Y: command.Args[i+5]}
+				}
+				current = b.P3
+				results = append(results, b)
+			}
+		case "A", "a":
+			for i := 0; i < len(command.Args); i += 7 {
+				rx := command.Args[i]
+				ry := command.Args[i+1]
+				xAxisRotation := command.Args[i+2]
+				largeArcFlag := int(command.Args[i+3])
+				sweepFlag := int(command.Args[i+4])
+				x := command.Args[i+5]
+				y := command.Args[i+6]
+				if command.Type == "a" {
+					x += current.X
+					y += current.Y
+				}
+
+				beziers := ArcToBeziers(current, rx, ry, xAxisRotation, largeArcFlag, sweepFlag, x, y)
+				for _, b := range beziers {
+					results = append(results, b)
+					current = b.P3
+				}
+			}
+		case "Z", "z":
+			if zPoint != nil {
+				results = append(results, lineBezier(current, *zPoint))
+				current = *zPoint
+			}
+		}
+	}
+	return results
+}
@@ -0,0 +1,86 @@
+package geom
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGetBeziersFromCommandsSimple(t *testing.T) {
+	beziers := GetBeziersFromCommands(mustParseD(t, simplePath))
+	if len(beziers) != 1 {
+		t.Error("len(beziers) must be 1")
+	}
+}
+
+func TestGetBeziersFromCommandsZ(t *testing.T) {
+	const pathWithZ = "M 10 10 C 120 20, 180 20, 170 10 Z"
+	got := GetBeziersFromCommands(mustParseD(t, pathWithZ))
+	wantStart := Point{X: 170, Y: 10}
+	wantEnd := Point{X: 10, Y: 10}
+	if len(got) != 2 {
+		t.Error("len(got) must be 2")
+	}
+	if got[1].P0.X != wantStart.X && got[1].P0.Y != wantStart.Y {
+		t.Error("got[1].P0 is different from wantStart")
+	}
+	if got[1].P3.X != wantEnd.X && got[1].P3.Y != wantEnd.Y {
+		t.Error("got[1].P3 is different from wantEnd")
+	}
+}
+
+func TestGetBeziersFromCommandsComplex(t *testing.T) {
+	beziers := GetBeziersFromCommands(mustParseD(t, complexPath))
+	if len(beziers) != 12 {
+		t.Errorf("len(beziers) must be 12, it is %d", len(beziers))
+	}
+}
+
+func TestGetPointFromBezier(t *testing.T) {
+	bezier := Bezier{
+		P0: Point{X: 0, Y: 0},
+		P1: Point{X: 0, Y: 10},
+		P2: Point{X: 10, Y: 10},
+		P3: Point{X: 10, Y: 0},
+	}
+
+	tests := []struct {
+		t        float64
+		expected Point
+	}{
+		{0, Point{X: 0, Y: 0}},
+		{0.5, Point{X: 5, Y: 7.5}},
+		{1, Point{X: 10, Y: 0}},
+	}
+
+	for _, tc := range tests {
+		p := GetPointFromBezier(bezier, tc.t)
+		if math.Abs(p.X-tc.expected.X) > 1e-9 || math.Abs(p.Y-tc.expected.Y) > 1e-9 {
+			t.Errorf("t=%.2f → got (%.3f, %.3f), expected (%.3f, %.3f)",
+				tc.t, p.X, p.Y, tc.expected.X, tc.expected.Y)
+		}
+	}
+}
+
+func TestGetBeziersFromCommandsLines(t *testing.T) {
+	got := GetBeziersFromCommands(mustParseD(t, "M 0 0 10 0 V 10 H 0 Z"))
+	want := []Point{{X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}, {X: 0, Y: 0}}
+	if len(got) != len(want) {
+		t.Fatalf("len(got) must be %d, it is %d", len(want), len(got))
+	}
+	for i, b := range got {
+		if b.P3 != want[i] {
+			t.Errorf("segment %d ends on %v expected %v", i, b.P3, want[i])
+		}
+	}
+}
+
+func TestGetBeziersFromCommandsSubpaths(t *testing.T) {
+	// Z closes to the most recent moveto, and the next subpath starts there
+	got := GetBeziersFromCommands(mustParseD(t, "M 0 0 L 5 0 M 10 0 L 15 0 Z L 10 5 Z"))
+	if len(got) != 5 {
+		t.Fatalf("len(got) must be 5, it is %d", len(got))
+	}
+	if got[2].P3 != (Point{X: 10, Y: 0}) || got[4].P3 != (Point{X: 10, Y: 0}) {
+		t.Errorf("Z must close on (10, 0), got %v and %v", got[2].P3, got[4].P3)
+	}
+}
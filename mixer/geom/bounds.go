@@ -0,0 +1,98 @@
+package geom
+
+import "math"
+
+// At returns the exact point of the curve at t, unlike GetPointFromBezier
+// which rounds it
+func (b Bezier) At(t float64) Point {
+	mt := 1 - t
+	return Point{
+		X: mt*mt*mt*b.P0.X + 3*mt*mt*t*b.P1.X + 3*mt*t*t*b.P2.X + t*t*t*b.P3.X,
+		Y: mt*mt*mt*b.P0.Y + 3*mt*mt*t*b.P1.Y + 3*mt*t*t*b.P2.Y + t*t*t*b.P3.Y,
+	}
+}
+
+// Bounds returns the exact bounding box of the curve: its end points and the
+// points where the derivative of x or y is zero
+func (b Bezier) Bounds() Rect {
+	rect := Rect{TopLeft: b.P0, BottomRight: b.P0}
+	rect = rect.Extend(b.P3)
+	for _, t := range extremaParameters(b.P0.X, b.P1.X, b.P2.X, b.P3.X) {
+		rect = rect.Extend(b.At(t))
+	}
+	for _, t := range extremaParameters(b.P0.Y, b.P1.Y, b.P2.Y, b.P3.Y) {
+		rect = rect.Extend(b.At(t))
+	}
+	return rect
+}
+
+// extremaParameters returns the t in ]0, 1[ where the derivative of the cubic
+// polynomial with the given control values is zero
+func extremaParameters(p0, p1, p2, p3 float64) []float64 {
+	// derivative / 3 = a*t^2 + b*t + c
+	a := p3 - 3*p2 + 3*p1 - p0
+	b := 2 * (p2 - 2*p1 + p0)
+	c := p1 - p0
+	roots := make([]float64, 0, 2)
+	const epsilon = 1e-12
+	if math.Abs(a) < epsilon {
+		if math.Abs(b) > epsilon {
+			roots = append(roots, -c/b)
+		}
+	} else {
+		delta := b*b - 4*a*c
+		if delta >= 0 {
+			sq := math.Sqrt(delta)
+			roots = append(roots, (-b+sq)/(2*a), (-b-sq)/(2*a))
+		}
+	}
+	results := roots[:0]
+	for _, t := range roots {
+		if t > 0 && t < 1 {
+			results = append(results, t)
+		}
+	}
+	return results
+}
+
+// BeziersBounds returns the bounding box of all the curves, false when there
+// is none
+func BeziersBounds(beziers []Bezier) (Rect, bool) {
+	if len(beziers) == 0 {
+		return Rect{}, false
+	}
+	rect := beziers[0].Bounds()
+	for _, b := range beziers[1:] {
+		rect = rect.Union(b.Bounds())
+	}
+	return rect, true
+}
+
+// Extend returns the smallest rectangle containing r and p
+func (r Rect) Extend(p Point) Rect {
+	return Rect{
+		TopLeft:     Point{X: math.Min(r.TopLeft.X, p.X), Y: math.Min(r.TopLeft.Y, p.Y)},
+		BottomRight: Point{X: math.Max(r.BottomRight.X, p.X), Y: math.Max(r.BottomRight.Y, p.Y)},
+	}
+}
+
+// Union returns the smallest rectangle containing r and r1
+func (r Rect) Union(r1 Rect) Rect {
+	return r.Extend(r1.TopLeft).Extend(r1.BottomRight)
+}
+
+// Inset moves every side of r by d towards its center, a negative d grows r
+func (r Rect) Inset(d float64) Rect {
+	return Rect{
+		TopLeft:     Point{X: r.TopLeft.X + d, Y: r.TopLeft.Y + d},
+		BottomRight: Point{X: r.BottomRight.X - d, Y: r.BottomRight.Y - d},
+	}
+}
+
+func (r Rect) Width() float64 {
+	return r.BottomRight.X - r.TopLeft.X
+}
+
+func (r Rect) Height() float64 {
+	return r.BottomRight.Y - r.TopLeft.Y
+}
@@ -0,0 +1,61 @@
+package geom
+
+import (
+	"math"
+	"testing"
+)
+
+func rectsClose(a, b Rect) bool {
+	return pointsClose(a.TopLeft, b.TopLeft) && pointsClose(a.BottomRight, b.BottomRight)
+}
+
+func TestBezierBounds(t *testing.T) {
+	tests := []struct {
+		name   string
+		bezier Bezier
+		want   Rect
+	}{
+		// the curve peaks at y = 7.5 for t = 0.5
+		{"arch", Bezier{P0: Point{X: 0, Y: 0}, P1: Point{X: 0, Y: 10}, P2: Point{X: 10, Y: 10}, P3: Point{X: 10, Y: 0}}, Rect{TopLeft: Point{X: 0, Y: 0}, BottomRight: Point{X: 10, Y: 7.5}}},
+		{"line", lineBezier(Point{X: 5, Y: -2}, Point{X: -3, Y: 4}), Rect{TopLeft: Point{X: -3, Y: -2}, BottomRight: Point{X: 5, Y: 4}}},
+	}
+	for _, tc := range tests {
+		if got := tc.bezier.Bounds(); !rectsClose(got, tc.want) {
+			t.Errorf("%s: got %v expected %v", tc.name, got, tc.want)
+		}
+	}
+
+	// the origin is not part of the box, and x extremes are inside the curve
+	got := Bezier{P0: Point{X: 130, Y: 10}, P1: Point{X: 120, Y: 20}, P2: Point{X: 180, Y: 20}, P3: Point{X: 170, Y: 10}}.Bounds()
+	tMin := (1 - math.Sqrt(10.0/14)) / 2
+	minX := 130*math.Pow(1-tMin, 3) + 360*math.Pow(1-tMin, 2)*tMin + 540*(1-tMin)*tMin*tMin + 170*math.Pow(tMin, 3)
+	// the curve is symmetric around x = 150
+	want := Rect{TopLeft: Point{X: minX, Y: 10}, BottomRight: Point{X: 300 - minX, Y: 17.5}}
+	if !rectsClose(got, want) {
+		t.Errorf("got %v expected %v", got, want)
+	}
+}
+
+func TestBezierBoundsMatchesSampling(t *testing.T) {
+	beziers := GetBeziersFromCommands(mustParseD(t, complexPath))
+	got, ok := BeziersBounds(beziers)
+	if !ok {
+		t.Fatal("expected a bounding box")
+	}
+	sampled := Rect{TopLeft: beziers[0].P0, BottomRight: beziers[0].P0}
+	for _, b := range beziers {
+		for i := 0; i <= 10000; i++ {
+			sampled = sampled.Extend(b.At(float64(i) / 10000))
+		}
+	}
+	if math.Abs(got.TopLeft.X-sampled.TopLeft.X) > 1e-6 || math.Abs(got.TopLeft.Y-sampled.TopLeft.Y) > 1e-6 ||
+		math.Abs(got.BottomRight.X-sampled.BottomRight.X) > 1e-6 || math.Abs(got.BottomRight.Y-sampled.BottomRight.Y) > 1e-6 {
+		t.Errorf("got %v expected %v", got, sampled)
+	}
+}
+
+func TestBeziersBoundsEmpty(t *testing.T) {
+	if _, ok := BeziersBounds(nil); ok {
+		t.Error("no curve must give no bounding box")
+	}
+}
@@ -0,0 +1,116 @@
+package geom
+
+import "math"
+
+// Projection is the point of a curve closest to another point
+type Projection struct {
+	T        float64 // curve parameter, in [0, 1]
+	Position Point
+	Tangent  Point // unit direction of the curve at T
+	Distance float64
+}
+
+// number of pieces a curve is cut into before refining the closest one
+const projectionSubdivisions = 32
+
+// Derivative returns the exact derivative of the curve at t
+func (b Bezier) Derivative(t float64) Point {
+	mt := 1 - t
+	return Point{
+		X: 3*mt*mt*(b.P1.X-b.P0.X) + 6*mt*t*(b.P2.X-b.P1.X) + 3*t*t*(b.P3.X-b.P2.X),
+		Y: 3*mt*mt*(b.P1.Y-b.P0.Y) + 6*mt*t*(b.P2.Y-b.P1.Y) + 3*t*t*(b.P3.Y-b.P2.Y),
+	}
+}
+
+func (b Bezier) secondDerivative(t float64) Point {
+	mt := 1 - t
+	return Point{
+		X: 6*mt*(b.P2.X-2*b.P1.X+b.P0.X) + 6*t*(b.P3.X-2*b.P2.X+b.P1.X),
+		Y: 6*mt*(b.P2.Y-2*b.P1.Y+b.P0.Y) + 6*t*(b.P3.Y-2*b.P2.Y+b.P1.Y),
+	}
+}
+
+// Tangent returns the unit direction of the curve at t. Where the derivative
+// vanishes (control point on an end point, as in lines) the direction of the
+// curve around t is used instead.
+func (b Bezier) Tangent(t float64) Point {
+	d := b.Derivative(t)
+	if d.Length() < 1e-9 {
+		d = b.At(math.Min(t+1e-4, 1)).Sub(b.At(math.Max(t-1e-4, 0)))
+	}
+	length := d.Length()
+	if length == 0 {
+		return Point{}
+	}
+	return Point{X: d.X / length, Y: d.Y / length}
+}
+
+// Project returns the point of the curve closest to p, in euclidean distance.
+// The curve is subdivided to find the candidates, each local minimum is then
+// refined with Newton's method.
+func (b Bezier) Project(p Point) Projection {
+	distance := func(t float64) float64 {
+		return b.At(t).Sub(p).Length()
+	}
+	samples := make([]float64, projectionSubdivisions+1)
+	for i := range samples {
+		samples[i] = distance(float64(i) / projectionSubdivisions)
+	}
+
+	best := 0.0
+	bestDistance := samples[0]
+	for i, d := range samples {
+		if (i > 0 && d > samples[i-1]) || (i < len(samples)-1 && d > samples[i+1]) {
+			continue
+		}
+		t := b.refineProjection(p, float64(i)/projectionSubdivisions)
+		if rd := distance(t); rd < d {
+			d = rd
+		} else {
+			t = float64(i) / projectionSubdivisions
+		}
+		if d < bestDistance {
+			best, bestDistance = t, d
+		}
+	}
+	return Projection{
+		T:        best,
+		Position: b.At(best),
+		Tangent:  b.Tangent(best),
+		Distance: bestDistance,
+	}
+}
+
+// refineProjection looks for the root of (B(t) - p).B'(t) around t
+func (b Bezier) refineProjection(p Point, t float64) float64 {
+	for i := 0; i < 8; i++ {
+		diff := b.At(t).Sub(p)
+		d1 := b.Derivative(t)
+		d2 := b.secondDerivative(t)
+		numerator := diff.X*d1.X + diff.Y*d1.Y
+		denominator := d1.X*d1.X + d1.Y*d1.Y + diff.X*d2.X + diff.Y*d2.Y
+		if denominator == 0 {
+			break
+		}
+		next := math.Max(0, math.Min(1, t-numerator/denominator))
+		if math.Abs(next-t) < 1e-12 {
+			return next
+		}
+		t = next
+	}
+	return t
+}
+
+// ProjectOnBeziers returns the index of the curve closest to p and the
+// projection on it, false when there are no curves
+func ProjectOnBeziers(beziers []Bezier, p Point) (int, Projection, bool) {
+	index := -1
+	var best Projection
+	for i, b := range beziers {
+		projection := b.Project(p)
+		if index < 0 || projection.Distance < best.Distance {
+			index, best = i, projection
+		}
+	}
+	return index, best, index >= 0
+}
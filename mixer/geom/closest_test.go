@@ -0,0 +1,44 @@
+package geom
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBezierProject(t *testing.T) {
+	b := Bezier{P0: Point{X: 130, Y: 10}, P1: Point{X: 120, Y: 20}, P2: Point{X: 180, Y: 20}, P3: Point{X: 170, Y: 10}}
+	for _, p := range []Point{{X: 150, Y: 0}, {X: 150, Y: 30}, {X: 120, Y: 12}, {X: 200, Y: 5}, {X: 140, Y: 16}} {
+		got := b.Project(p)
+		// no sample of the curve is closer than the projection
+		for u := 0.0; u <= 1; u += 0.0005 {
+			if d := b.At(u).Sub(p).Length(); d < got.Distance-1e-9 {
+				t.Errorf("%v: projection at t=%f is %f away, t=%f is %f away", p, got.T, got.Distance, u, d)
+				break
+			}
+		}
+		if !pointsClose(got.Position, b.At(got.T)) {
+			t.Errorf("%v: position %v is not the point at t=%f", p, got.Position, got.T)
+		}
+		if math.Abs(got.Tangent.Length()-1) > 1e-9 {
+			t.Errorf("%v: tangent %v is not a unit vector", p, got.Tangent)
+		}
+	}
+}
+
+func TestBezierProjectLine(t *testing.T) {
+	// lines have a zero derivative at their ends, the tangent is still known
+	b := lineBezier(Point{X: 0, Y: 0}, Point{X: 0, Y: 10})
+	got := b.Project(Point{X: 3, Y: 12})
+	want := Projection{T: 1, Position: Point{X: 0, Y: 10}, Tangent: Point{X: 0, Y: 1}, Distance: math.Sqrt(13)}
+	if math.Abs(got.T-want.T) > 1e-9 || !pointsClose(got.Position, want.Position) || !pointsClose(got.Tangent, want.Tangent) || math.Abs(got.Distance-want.Distance) > 1e-9 {
+		t.Errorf("got %v expected %v", got, want)
+	}
+
+	index, got, ok := ProjectOnBeziers([]Bezier{b, lineBezier(Point{X: 5, Y: 0}, Point{X: 5, Y: 10})}, Point{X: 4, Y: 5})
+	if !ok || index != 1 || !pointsClose(got.Position, Point{X: 5, Y: 5}) {
+		t.Errorf("got curve %d at %v expected curve 1 at (5, 5)", index, got.Position)
+	}
+	if _, _, ok := ProjectOnBeziers(nil, Point{}); ok {
+		t.Errorf("expected no projection without curves")
+	}
+}
@@ -0,0 +1,113 @@
+package geom
+
+import (
+	"strconv"
+	"strings"
+)
+
+type Command struct {
+	Type string
+	Args []float64
+}
+
+// End returns the point an absolute command ends on, current being the point
+// it starts from and start the beginning of the current subpath
+func (cmd Command) End(current, start Point) Point {
+	switch cmd.Type {
+	case "H":
+		return Point{X: cmd.Args[len(cmd.Args)-1], Y: current.Y}
+	case "V":
+		return Point{X: current.X, Y: cmd.Args[len(cmd.Args)-1]}
+	case "Z":
+		return start
+	}
+	if len(cmd.Args) < 2 {
+		return current
+	}
+	return Point{X: cmd.Args[len(cmd.Args)-2], Y: cmd.Args[len(cmd.Args)-1]}
+}
+
+// Transform moves an absolute command (as produced by ParseD) by m, current
+// being the point the command starts from before the transformation.
+// H and V are kept when m keeps horizontal (or vertical) lines as such, and
+// become L otherwise. Arc radii, rotation and sweep follow m.
+func (cmd *Command) Transform(m Matrix, current Point) {
+	switch cmd.Type {
+	case "M", "L", "C":
+		for u := 0; u < len(cmd.Args); u += 2 {
+			point := m.Apply(Point{X: cmd.Args[u], Y: cmd.Args[u+1]})
+			cmd.Args[u] = point.X
+			cmd.Args[u+1] = point.Y
+		}
+	case "H", "V":
+		keep := (cmd.Type == "H" && m.B == 0) || (cmd.Type == "V" && m.C == 0)
+		args := make([]float64, 0, 2*len(cmd.Args))
+		for _, arg := range cmd.Args {
+			if cmd.Type == "H" {
+				current.X = arg
+			} else {
+				current.Y = arg
+			}
+			point := m.Apply(current)
+			switch {
+			case !keep:
+				args = append(args, point.X, point.Y)
+			case cmd.Type == "H":
+				args = append(args, point.X)
+			default:
+				args = append(args, point.Y)
+			}
+		}
+		if !keep {
+			cmd.Type = "L"
+		}
+		cmd.Args = args
+	case "A":
+		for u := 0; u < len(cmd.Args); u += 7 {
+			rx, ry, rotation := m.transformArc(cmd.Args[u], cmd.Args[u+1], cmd.Args[u+2])
+			cmd.Args[u], cmd.Args[u+1], cmd.Args[u+2] = rx, ry, rotation
+			// a mirrored arc goes the other way around
+			if m.Determinant() < 0 {
+				cmd.Args[u+4] = 1 - cmd.Args[u+4]
+			}
+			point := m.Apply(Point{X: cmd.Args[u+5], Y: cmd.Args[u+6]})
+			cmd.Args[u+5] = point.X
+			cmd.Args[u+6] = point.Y
+		}
+	}
+}
+
+func CompileD(commands []Command) string {
+	results := make([]string, 0)
+	for _, command := range commands {
+		args := make([]string, 0)
+		if command.Type == "A" || command.Type == "a" {
+			for i := 0; i < len(command.Args); i += 7 {
+				rx := strconv.FormatFloat(command.Args[i], 'f', 8, 64)
+				ry := strconv.FormatFloat(command.Args[i+1], 'f', 8, 64)
+				rotation := strconv.FormatFloat(command.Args[i+2], 'f', 8, 64)
+
+				// Flags : large-arc et sweep, forcés à 0 ou 1
+				largeArcFlag := "0"
+				if command.Args[i+3] != 0 {
+					largeArcFlag = "1"
+				}
+				sweepFlag := "0"
+				if command.Args[i+4] != 0 {
+					sweepFlag = "1"
+				}
+
+				x := strconv.FormatFloat(command.Args[i+5], 'f', 8, 64)
+				y := strconv.FormatFloat(command.Args[i+6], 'f', 8, 64)
+
+				args = append(args, rx, ry, rotation, largeArcFlag, sweepFlag, x, y)
+			}
+		} else {
+			for _, arg := range command.Args {
+				args = append(args, strconv.FormatFloat(arg, 'f', 8, 64))
+			}
+		}
+		results = append(results, command.Type+" "+strings.Join(args, " "))
+	}
+	return strings.Join(results, " ")
+}
@@ -1,4 +1,4 @@
-package main
+package geom
 
 import (
 	"testing"
@@ -6,9 +6,9 @@ import (
 
 func TestCommandTransformSimple(t *testing.T) {
 	test := "M 0 0"
-	tr := Transformation{Translation: Point{X: 10, Y: 0}}
-	commands := ParseD(test)
-	commands[0].Transform(tr)
+	tr := TranslateMatrix(10, 0)
+	commands := mustParseD(t, test)
+	commands[0].Transform(tr, Point{})
 	if commands[0].Args[0] != 10 || commands[0].Args[1] != 0 {
 		t.Errorf("Bad transformed coordinates")
 	}
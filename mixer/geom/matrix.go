@@ -0,0 +1,213 @@
+package geom
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Matrix is a 2D affine transformation, with the same meaning as the SVG
+// matrix(a,b,c,d,e,f) transform:
+//
+//	x' = A*x + C*y + E
+//	y' = B*x + D*y + F
+type Matrix struct {
+	A, B, C, D, E, F float64
+}
+
+func Identity() Matrix {
+	return Matrix{A: 1, D: 1}
+}
+
+func TranslateMatrix(tx, ty float64) Matrix {
+	return Matrix{A: 1, D: 1, E: tx, F: ty}
+}
+
+func ScaleMatrix(sx, sy float64) Matrix {
+	return Matrix{A: sx, D: sy}
+}
+
+func RotateMatrix(angleDeg float64) Matrix {
+	angle := angleDeg * (math.Pi / 180)
+	cos, sin := math.Cos(angle), math.Sin(angle)
+	return Matrix{A: cos, B: sin, C: -sin, D: cos}
+}
+
+// MirrorMatrix reflects across the line through the origin with the given
+// angle, MirrorMatrix(90) flips left and right
+func MirrorMatrix(axisDeg float64) Matrix {
+	angle := 2 * axisDeg * (math.Pi / 180)
+	cos, sin := math.Cos(angle), math.Sin(angle)
+	return Matrix{A: cos, B: sin, C: sin, D: -cos}
+}
+
+func SkewXMatrix(angleDeg float64) Matrix {
+	return Matrix{A: 1, C: math.Tan(angleDeg * (math.Pi / 180)), D: 1}
+}
+
+func SkewYMatrix(angleDeg float64) Matrix {
+	return Matrix{A: 1, B: math.Tan(angleDeg * (math.Pi / 180)), D: 1}
+}
+
+// Multiply returns the transformation applying n first, then m
+func (m Matrix) Multiply(n Matrix) Matrix {
+	return Matrix{
+		A: m.A*n.A + m.C*n.B,
+		B: m.B*n.A + m.D*n.B,
+		C: m.A*n.C + m.C*n.D,
+		D: m.B*n.C + m.D*n.D,
+		E: m.A*n.E + m.C*n.F + m.E,
+		F: m.B*n.E + m.D*n.F + m.F,
+	}
+}
+
+func (m Matrix) Apply(p Point) Point {
+	return Point{
+		X: m.A*p.X + m.C*p.Y + m.E,
+		Y: m.B*p.X + m.D*p.Y + m.F,
+	}
+}
+
+func (m Matrix) IsIdentity() bool {
+	return m == Identity()
+}
+
+// TransformCommands returns absolute commands (as produced by ParseD) moved
+// by m, commands is left untouched
+func (m Matrix) TransformCommands(commands []Command) []Command {
+	results := make([]Command, 0, len(commands))
+	current := Point{}
+	start := Point{}
+	for _, cmd := range commands {
+		end := cmd.End(current, start)
+		if cmd.Type == "M" {
+			start = Point{X: cmd.Args[0], Y: cmd.Args[1]}
+		}
+		transformed := Command{Type: cmd.Type, Args: append([]float64{}, cmd.Args...)}
+		transformed.Transform(m, current)
+		results = append(results, transformed)
+		current = end
+	}
+	return results
+}
+
+func (m Matrix) Determinant() float64 {
+	return m.A*m.D - m.B*m.C
+}
+
+// Inverse returns the transformation undoing m, m must not be degenerate
+// (zero determinant)
+func (m Matrix) Inverse() Matrix {
+	det := m.Determinant()
+	return Matrix{
+		A: m.D / det,
+		B: -m.B / det,
+		C: -m.C / det,
+		D: m.A / det,
+		E: (m.C*m.F - m.D*m.E) / det,
+		F: (m.B*m.E - m.A*m.F) / det,
+	}
+}
+
+// transformArc returns the radii and x axis rotation (degrees) of the ellipse
+// of an arc once transformed by m, translation left aside
+func (m Matrix) transformArc(rx, ry, rotationDeg float64) (float64, float64, float64) {
+	phi := rotationDeg * (math.Pi / 180)
+	cos, sin := math.Cos(phi), math.Sin(phi)
+	// columns of the transformed ellipse axes
+	a := (m.A*cos + m.C*sin) * rx
+	b := (m.B*cos + m.D*sin) * rx
+	c := (-m.A*sin + m.C*cos) * ry
+	d := (-m.B*sin + m.D*cos) * ry
+	// the new radii and rotation are the eigen values and vectors of
+	// [a c; b d] * [a c; b d]^T
+	p := a*a + c*c
+	q := b*b + d*d
+	r := a*b + c*d
+	mean := (p + q) / 2
+	delta := math.Sqrt((p-q)*(p-q)/4 + r*r)
+	major := math.Sqrt(mean + delta)
+	minor := math.Sqrt(math.Max(mean-delta, 0))
+	rotation := 0.5 * math.Atan2(2*r, p-q) * 180 / math.Pi
+
+	// keep rx on the image of the original x axis, so a plain translation or
+	// rotation doesn't swap the radii
+	ux, uy := math.Cos(rotation*math.Pi/180), math.Sin(rotation*math.Pi/180)
+	if math.Abs(a*ux+b*uy)*math.Hypot(c, d) < math.Abs(c*ux+d*uy)*math.Hypot(a, b) {
+		major, minor = minor, major
+		rotation += 90
+	}
+	// an ellipse rotated by 180° is the same, stay close to the x axis image
+	reference := math.Atan2(b, a) * 180 / math.Pi
+	rotation = reference + math.Remainder(rotation-reference, 180)
+	return major, minor, rotation
+}
+
+// ParseTransform parses the value of an SVG transform attribute, a list of
+// matrix, translate, scale, rotate, skewX and skewY functions applied from
+// right to left
+func ParseTransform(s string) (Matrix, error) {
+	result := Identity()
+	rest := s
+	for {
+		rest = strings.TrimLeft(rest, " \t\n\r,")
+		if rest == "" {
+			return result, nil
+		}
+		offset := len(s) - len(rest)
+		open := strings.IndexByte(rest, '(')
+		closing := strings.IndexByte(rest, ')')
+		if open < 0 || closing < open {
+			return Matrix{}, &SyntaxError{Offset: offset, Msg: "transform: expected name(arguments)"}
+		}
+		name := strings.TrimSpace(rest[:open])
+		args, err := parseTransformArgs(rest[open+1 : closing])
+		if err != nil {
+			return Matrix{}, &SyntaxError{Offset: offset + open + 1, Msg: "transform: " + err.Error()}
+		}
+		m, ok := transformFunction(name, args)
+		if !ok {
+			return Matrix{}, &SyntaxError{Offset: offset, Msg: "transform: bad " + strconv.Quote(rest[:closing+1])}
+		}
+		result = result.Multiply(m)
+		rest = rest[closing+1:]
+	}
+}
+
+func parseTransformArgs(s string) ([]float64, error) {
+	scanner := &pathScanner{d: s}
+	args := make([]float64, 0)
+	for !scanner.done() {
+		arg, err := scanner.number()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	return args, nil
+}
+
+func transformFunction(name string, args []float64) (Matrix, bool) {
+	switch {
+	case name == "matrix" && len(args) == 6:
+		return Matrix{A: args[0], B: args[1], C: args[2], D: args[3], E: args[4], F: args[5]}, true
+	case name == "translate" && len(args) == 1:
+		return TranslateMatrix(args[0], 0), true
+	case name == "translate" && len(args) == 2:
+		return TranslateMatrix(args[0], args[1]), true
+	case name == "scale" && len(args) == 1:
+		return ScaleMatrix(args[0], args[0]), true
+	case name == "scale" && len(args) == 2:
+		return ScaleMatrix(args[0], args[1]), true
+	case name == "rotate" && len(args) == 1:
+		return RotateMatrix(args[0]), true
+	case name == "rotate" && len(args) == 3:
+		// rotation around (cx, cy)
+		return TranslateMatrix(args[1], args[2]).Multiply(RotateMatrix(args[0])).Multiply(TranslateMatrix(-args[1], -args[2])), true
+	case name == "skewX" && len(args) == 1:
+		return SkewXMatrix(args[0]), true
+	case name == "skewY" && len(args) == 1:
+		return SkewYMatrix(args[0]), true
+	}
+	return Matrix{}, false
+}
@@ -0,0 +1,109 @@
+package geom
+
+import (
+	"math"
+	"testing"
+)
+
+func pointsClose(a, b Point) bool {
+	return math.Abs(a.X-b.X) < 1e-9 && math.Abs(a.Y-b.Y) < 1e-9
+}
+
+func TestParseTransform(t *testing.T) {
+	tests := []struct {
+		transform string
+		in        Point
+		want      Point
+	}{
+		{"", Point{X: 1, Y: 2}, Point{X: 1, Y: 2}},
+		{"translate(10)", Point{X: 1, Y: 2}, Point{X: 11, Y: 2}},
+		{"translate(-5.5,10)", Point{X: 1, Y: 2}, Point{X: -4.5, Y: 12}},
+		{"scale(-1,1)", Point{X: 3, Y: 2}, Point{X: -3, Y: 2}},
+		{"scale(2)", Point{X: 3, Y: 2}, Point{X: 6, Y: 4}},
+		{"rotate(90)", Point{X: 1, Y: 0}, Point{X: 0, Y: 1}},
+		{"rotate(90 10 10)", Point{X: 11, Y: 10}, Point{X: 10, Y: 11}},
+		{"skewX(45)", Point{X: 0, Y: 1}, Point{X: 1, Y: 1}},
+		{"skewY(45)", Point{X: 1, Y: 0}, Point{X: 1, Y: 1}},
+		{"matrix(1,0,0,0.5,34,11)", Point{X: 1, Y: 2}, Point{X: 35, Y: 12}},
+		// the rightmost function is applied first
+		{"translate(10,0) scale(2)", Point{X: 1, Y: 1}, Point{X: 12, Y: 2}},
+		{"scale(2),translate(10,0)", Point{X: 1, Y: 1}, Point{X: 22, Y: 2}},
+	}
+	for _, tc := range tests {
+		m, err := ParseTransform(tc.transform)
+		if err != nil {
+			t.Errorf("%q: %v", tc.transform, err)
+			continue
+		}
+		if got := m.Apply(tc.in); !pointsClose(got, tc.want) {
+			t.Errorf("%q: got %v expected %v", tc.transform, got, tc.want)
+		}
+	}
+}
+
+func TestParseTransformErrors(t *testing.T) {
+	for _, transform := range []string{"translate(1", "scale()", "rotate(1,2)", "spin(3)", "matrix(1,0,0,1,x,0)"} {
+		if _, err := ParseTransform(transform); err == nil {
+			t.Errorf("%q: expected an error", transform)
+		}
+	}
+}
+
+func TestMatrixTransformCommands(t *testing.T) {
+	m := RotateMatrix(90)
+	got := m.TransformCommands(mustParseD(t, "M 0 0 H 10 V 10 Z"))
+	want := []Command{{"M", []float64{0, 0}}, {"L", []float64{0, 10}}, {"L", []float64{-10, 10}}, {"Z", []float64{}}}
+	if !commandsEqual(got, want) {
+		t.Errorf("got %v expected %v", got, want)
+	}
+
+	// H and V are kept when they stay horizontal and vertical
+	got = ScaleMatrix(2, 3).TransformCommands(mustParseD(t, "M 1 1 H 10 V 10"))
+	want = []Command{{"M", []float64{2, 3}}, {"H", []float64{20}}, {"V", []float64{30}}}
+	if !commandsEqual(got, want) {
+		t.Errorf("got %v expected %v", got, want)
+	}
+
+	// under a skew, H depends on the current y
+	got = SkewXMatrix(45).TransformCommands(mustParseD(t, "M 0 2 H 10 V 5"))
+	want = []Command{{"M", []float64{2, 2}}, {"H", []float64{12}}, {"L", []float64{15, 5}}}
+	if !commandsEqual(got, want) {
+		t.Errorf("got %v expected %v", got, want)
+	}
+}
+
+func TestMatrixTransformArcs(t *testing.T) {
+	tests := []struct {
+		name string
+		m    Matrix
+		d    string
+		want []float64
+	}{
+		{"translate", TranslateMatrix(3, 4), "M 0 0 A 2 1 30 0 1 10 0", []float64{2, 1, 30, 0, 1, 13, 4}},
+		{"rotate", RotateMatrix(90), "M 0 0 A 2 1 0 1 0 10 0", []float64{2, 1, 90, 1, 0, 0, 10}},
+		{"scale", ScaleMatrix(2, 1), "M 0 0 A 5 5 0 0 1 10 0", []float64{10, 5, 0, 0, 1, 20, 0}},
+		// a mirror reverses the sweep
+		{"mirror", MirrorMatrix(90), "M 0 0 A 2 1 20 0 1 10 0", []float64{2, 1, 160, 0, 0, -10, 0}},
+		{"skew", SkewXMatrix(45), "M 0 0 A 1 1 0 0 1 2 0", []float64{math.Sqrt(1.5 - math.Sqrt(1.25)), math.Sqrt(1.5 + math.Sqrt(1.25)), math.Atan2(2, 1)*90/math.Pi - 90, 0, 1, 2, 0}},
+	}
+	for _, tc := range tests {
+		got := tc.m.TransformCommands(mustParseD(t, tc.d))
+		if len(got) != 2 || !commandsEqual(got[1:], []Command{{"A", tc.want}}) {
+			t.Errorf("%s: got %v expected A %v", tc.name, got[1:], tc.want)
+		}
+	}
+}
+
+func TestMatrixInverse(t *testing.T) {
+	m, err := ParseTransform("matrix(1,0,0,0.87432367,34.089207,11.449856) rotate(30) skewX(10)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := Point{X: 3, Y: -7}
+	if got := m.Inverse().Apply(m.Apply(p)); !pointsClose(got, p) {
+		t.Errorf("got %v expected %v", got, p)
+	}
+	if !pointsClose(MirrorMatrix(90).Apply(Point{X: 3, Y: 1}), Point{X: -3, Y: 1}) {
+		t.Error("MirrorMatrix(90) must flip left and right")
+	}
+}
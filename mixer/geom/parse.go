@@ -0,0 +1,324 @@
+package geom
+
+import (
+	"strconv"
+	"strings"
+)
+
+// SyntaxError reports malformed path data, Offset is the byte index in the
+// d attribute where the problem was found
+type SyntaxError struct {
+	Offset int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return "path data: offset " + strconv.Itoa(e.Offset) + ": " + e.Msg
+}
+
+// number of arguments taken by each command
+var commandArity = map[byte]int{
+	'M': 2, 'L': 2, 'T': 2,
+	'H': 1, 'V': 1,
+	'C': 6,
+	'S': 4, 'Q': 4,
+	'A': 7,
+	'Z': 0,
+}
+
+// pathScanner splits path data into command letters and numbers following
+// the SVG path grammar: separators are optional, numbers can be glued together
+// ("1-2", "0.5.5"), use exponents, and arc flags can be a single digit
+type pathScanner struct {
+	d   string
+	pos int
+}
+
+func (s *pathScanner) skipSeparators() {
+	for s.pos < len(s.d) {
+		switch s.d[s.pos] {
+		case ' ', '\t', '\n', '\r', '\f', ',':
+			s.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (s *pathScanner) done() bool {
+	s.skipSeparators()
+	return s.pos >= len(s.d)
+}
+
+// command returns the next command letter, if the next token is one
+func (s *pathScanner) command() (byte, bool) {
+	s.skipSeparators()
+	if s.pos >= len(s.d) {
+		return 0, false
+	}
+	c := s.d[s.pos]
+	if _, ok := commandArity[upper(c)]; !ok {
+		return 0, false
+	}
+	s.pos++
+	return c, true
+}
+
+func (s *pathScanner) number() (float64, error) {
+	s.skipSeparators()
+	start := s.pos
+	i := s.pos
+	if i < len(s.d) && (s.d[i] == '+' || s.d[i] == '-') {
+		i++
+	}
+	digits := 0
+	for i < len(s.d) && isDigit(s.d[i]) {
+		i++
+		digits++
+	}
+	if i < len(s.d) && s.d[i] == '.' {
+		i++
+		for i < len(s.d) && isDigit(s.d[i]) {
+			i++
+			digits++
+		}
+	}
+	if digits == 0 {
+		return 0, s.unexpected(start)
+	}
+	if i < len(s.d) && (s.d[i] == 'e' || s.d[i] == 'E') {
+		j := i + 1
+		if j < len(s.d) && (s.d[j] == '+' || s.d[j] == '-') {
+			j++
+		}
+		if j < len(s.d) && isDigit(s.d[j]) {
+			for j < len(s.d) && isDigit(s.d[j]) {
+				j++
+			}
+			i = j
+		}
+	}
+	number, err := strconv.ParseFloat(s.d[start:i], 64)
+	if err != nil {
+		return 0, &SyntaxError{Offset: start, Msg: "bad number " + strconv.Quote(s.d[start:i])}
+	}
+	s.pos = i
+	return number, nil
+}
+
+// flag reads an arc flag, which is a single 0 or 1 that needs no separator
+func (s *pathScanner) flag() (float64, error) {
+	s.skipSeparators()
+	if s.pos < len(s.d) && (s.d[s.pos] == '0' || s.d[s.pos] == '1') {
+		s.pos++
+		return float64(s.d[s.pos-1] - '0'), nil
+	}
+	return 0, &SyntaxError{Offset: s.pos, Msg: "arc flags must be 0 or 1"}
+}
+
+// startsNumber reports if the next token is a number, meaning the current
+// command is implicitly repeated
+func (s *pathScanner) startsNumber() bool {
+	s.skipSeparators()
+	if s.pos >= len(s.d) {
+		return false
+	}
+	c := s.d[s.pos]
+	return isDigit(c) || c == '.' || c == '-' || c == '+'
+}
+
+func (s *pathScanner) unexpected(offset int) error {
+	if offset >= len(s.d) {
+		return &SyntaxError{Offset: offset, Msg: "unexpected end of path data"}
+	}
+	return &SyntaxError{Offset: offset, Msg: "unexpected " + strconv.Quote(string(s.d[offset]))}
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func upper(c byte) byte {
+	if c >= 'a' && c <= 'z' {
+		return c - 'a' + 'A'
+	}
+	return c
+}
+
+// tokenize groups path data into commands as written, relative ones included.
+// Extra coordinate pairs after a moveto become a lineto of the same kind.
+func tokenize(d string) ([]Command, error) {
+	s := &pathScanner{d: d}
+	commands := make([]Command, 0)
+	for !s.done() {
+		offset := s.pos
+		c, ok := s.command()
+		if !ok {
+			if len(commands) == 0 {
+				return nil, &SyntaxError{Offset: offset, Msg: "path data must start with a moveto"}
+			}
+			return nil, s.unexpected(offset)
+		}
+		if len(commands) == 0 && upper(c) != 'M' {
+			return nil, &SyntaxError{Offset: offset, Msg: "path data must start with a moveto"}
+		}
+		arity := commandArity[upper(c)]
+		cmd := Command{Type: string(c), Args: make([]float64, 0, arity)}
+		if arity == 0 {
+			commands = append(commands, cmd)
+			continue
+		}
+		for first := true; first || s.startsNumber(); first = false {
+			for u := 0; u < arity; u++ {
+				var arg float64
+				var err error
+				if upper(c) == 'A' && (u == 3 || u == 4) {
+					arg, err = s.flag()
+				} else {
+					arg, err = s.number()
+				}
+				if err != nil {
+					return nil, err
+				}
+				cmd.Args = append(cmd.Args, arg)
+			}
+			if upper(c) == 'M' && first {
+				commands = append(commands, cmd)
+				lineto := "L"
+				if c == 'm' {
+					lineto = "l"
+				}
+				cmd = Command{Type: lineto, Args: make([]float64, 0, 2)}
+				c = lineto[0]
+				arity = 2
+			}
+		}
+		if len(cmd.Args) > 0 {
+			commands = append(commands, cmd)
+		}
+	}
+	return commands, nil
+}
+
+// ParseD parses the d attribute of a path into commands that only use
+// absolute coordinates. Smooth and quadratic curves (S, Q, T) are converted
+// to cubic ones (C), so the result only contains M, L, H, V, C, A and Z.
+func ParseD(d string) ([]Command, error) {
+	commands, err := tokenize(d)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]Command, 0, len(commands))
+	current := Point{}
+	start := Point{}
+	// control point to reflect for the next S or T command
+	var lastCubic, lastQuad *Point
+
+	for _, cmd := range commands {
+		relative := cmd.Type != strings.ToUpper(cmd.Type)
+		origin := func() Point {
+			if relative {
+				return current
+			}
+			return Point{}
+		}
+		abs := Command{Type: strings.ToUpper(cmd.Type), Args: make([]float64, 0, len(cmd.Args))}
+		var nextCubic, nextQuad *Point
+
+		switch abs.Type {
+		case "M", "L":
+			for u := 0; u < len(cmd.Args); u += 2 {
+				o := origin()
+				current = Point{X: cmd.Args[u] + o.X, Y: cmd.Args[u+1] + o.Y}
+				abs.Args = append(abs.Args, current.X, current.Y)
+			}
+			if abs.Type == "M" {
+				start = current
+			}
+
+		case "H":
+			for _, x := range cmd.Args {
+				current.X = x + origin().X
+				abs.Args = append(abs.Args, current.X)
+			}
+
+		case "V":
+			for _, y := range cmd.Args {
+				current.Y = y + origin().Y
+				abs.Args = append(abs.Args, current.Y)
+			}
+
+		case "C":
+			for u := 0; u < len(cmd.Args); u += 6 {
+				o := origin()
+				p1 := Point{X: cmd.Args[u] + o.X, Y: cmd.Args[u+1] + o.Y}
+				p2 := Point{X: cmd.Args[u+2] + o.X, Y: cmd.Args[u+3] + o.Y}
+				p3 := Point{X: cmd.Args[u+4] + o.X, Y: cmd.Args[u+5] + o.Y}
+				abs.Args = append(abs.Args, p1.X, p1.Y, p2.X, p2.Y, p3.X, p3.Y)
+				current = p3
+				nextCubic = &p2
+			}
+
+		case "S":
+			abs.Type = "C"
+			for u := 0; u < len(cmd.Args); u += 4 {
+				o := origin()
+				p1 := current
+				if lastCubic != nil {
+					p1 = current.Add(current.Sub(*lastCubic))
+				}
+				p2 := Point{X: cmd.Args[u] + o.X, Y: cmd.Args[u+1] + o.Y}
+				p3 := Point{X: cmd.Args[u+2] + o.X, Y: cmd.Args[u+3] + o.Y}
+				abs.Args = append(abs.Args, p1.X, p1.Y, p2.X, p2.Y, p3.X, p3.Y)
+				current = p3
+				lastCubic = &p2
+				nextCubic = &p2
+			}
+
+		case "Q", "T":
+			arity := commandArity[abs.Type[0]]
+			abs.Type = "C"
+			for u := 0; u < len(cmd.Args); u += arity {
+				o := origin()
+				var q Point
+				if arity == 4 {
+					q = Point{X: cmd.Args[u] + o.X, Y: cmd.Args[u+1] + o.Y}
+				} else if lastQuad != nil {
+					q = current.Add(current.Sub(*lastQuad))
+				} else {
+					q = current
+				}
+				p3 := Point{X: cmd.Args[u+arity-2] + o.X, Y: cmd.Args[u+arity-1] + o.Y}
+				p1, p2 := quadToCubic(current, q, p3)
+				abs.Args = append(abs.Args, p1.X, p1.Y, p2.X, p2.Y, p3.X, p3.Y)
+				current = p3
+				lastQuad = &q
+				nextQuad = &q
+			}
+
+		case "A":
+			for u := 0; u < len(cmd.Args); u += 7 {
+				o := origin()
+				current = Point{X: cmd.Args[u+5] + o.X, Y: cmd.Args[u+6] + o.Y}
+				abs.Args = append(abs.Args, cmd.Args[u:u+5]...)
+				abs.Args = append(abs.Args, current.X, current.Y)
+			}
+
+		case "Z":
+			current = start
+		}
+
+		lastCubic, lastQuad = nextCubic, nextQuad
+		results = append(results, abs)
+	}
+
+	return results, nil
+}
+
+// quadToCubic returns the control points of the cubic curve drawing the same
+// curve as the quadratic one from p0 to p2 controlled by q
+func quadToCubic(p0, q, p2 Point) (Point, Point) {
+	return Point{X: p0.X + 2.0/3.0*(q.X-p0.X), Y: p0.Y + 2.0/3.0*(q.Y-p0.Y)},
+		Point{X: p2.X + 2.0/3.0*(q.X-p2.X), Y: p2.Y + 2.0/3.0*(q.Y-p2.Y)}
+}
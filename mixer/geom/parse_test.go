@@ -0,0 +1,145 @@
+package geom
+
+import (
+	"math"
+	"testing"
+)
+
+const simplePath = "M 130 10 C 120 20, 180 20, 170 10"
+const intermediatePath = "m 10 10 c 1 2 3 4 5 6 z m -10 -10"
+const complexPath = " m 0 0 c 2.384706 -3.8247189 9.090522 -2.8303014 13.419508 -2.9698914 4.328986 -0.13959 8.777591 -0.1227708 12.099557 1.8699314 3.321966 1.992702 5.665494 5.552875 6.599759 8.799678 0.934265 3.246803 0.907 6.146902 -0.769972 9.239661 -1.401888 2.585435 -4.005582 4.719819 -7.320097 6.355104 -0.650388 0.320882 -1.328144 0.622547 -2.029561 0.904631 -4.275988 1.719648 -11.763687 4.446312 -15.729423 1.649939 -0.37043 -0.261202 -0.689892 -0.548786 -0.965528 -0.859054 -2.675262 -3.011389 -1.222003 -8.159713 -2.169357 -12.065472 -0.706428 -2.912467 -2.980282 -6.045882 -3.64267 -8.952141 -0.317429 -1.392734 -0.264783 -2.733301 0.507784 -3.972386 z"
+
+func TestParseDSimple(t *testing.T) {
+	test := simplePath
+	commands := mustParseD(t, test)
+	if len(commands) != 2 {
+		t.Error("len(commands) must be 2")
+	}
+	if commands[0].Type != "M" || commands[1].Type != "C" {
+		t.Error("Wrong command types")
+	}
+}
+
+func TestParseDIntermediate(t *testing.T) {
+	test := intermediatePath
+	commands := mustParseD(t, test)
+	if len(commands) != 4 {
+		t.Error("len(commands) must be 4")
+	}
+	if commands[0].Type != "M" || commands[1].Type != "C" || commands[2].Type != "Z" || commands[3].Type != "M" {
+		t.Error("Wrong command types")
+	}
+	if commands[0].Args[0] != 10 || commands[0].Args[1] != 10 || commands[3].Args[0] != 0 || commands[3].Args[1] != 0 {
+		t.Error("Wrong M Args")
+	}
+	if commands[1].Args[0] != 11 || commands[1].Args[1] != 12 || commands[1].Args[2] != 13 || commands[1].Args[3] != 14 || commands[1].Args[4] != 15 || commands[1].Args[5] != 16 {
+		t.Error("Wrong C Args")
+	}
+}
+
+func TestParseDComplex(t *testing.T) {
+	test := complexPath
+	commands := mustParseD(t, test)
+	if len(commands) != 3 {
+		t.Error("len(commands) must be 3")
+	}
+	if commands[0].Type != "M" || commands[1].Type != "C" || commands[2].Type != "Z" {
+		t.Error("Wrong command types")
+	}
+}
+
+func TestParseDErrors(t *testing.T) {
+	tests := []struct {
+		d      string
+		offset int
+	}{
+		{"M 10 x", 5},
+		{"10 10", 0},
+		{"M 10 10 C 1 2 3", 15},
+		{"M 10 10 K 1 2", 8},
+		{"M 10 10 Z 3", 10},
+		{"L 10 10", 0},
+		{"M 10 10 A 1 1 0 2 1 5 5", 16},
+		{"M 10 10 L 1e 2", 11},
+	}
+	for _, tc := range tests {
+		_, err := ParseD(tc.d)
+		syntaxErr, ok := err.(*SyntaxError)
+		if !ok {
+			t.Errorf("%q: expected a *SyntaxError, got %v", tc.d, err)
+			continue
+		}
+		if syntaxErr.Offset != tc.offset {
+			t.Errorf("%q: got offset %d expected %d", tc.d, syntaxErr.Offset, tc.offset)
+		}
+	}
+}
+
+func TestParseDCompact(t *testing.T) {
+	tests := []struct {
+		d    string
+		want []Command
+	}{
+		{"M10,10L20,20", []Command{{"M", []float64{10, 10}}, {"L", []float64{20, 20}}}},
+		{"M1-2l3-4", []Command{{"M", []float64{1, -2}}, {"L", []float64{4, -6}}}},
+		{"M.5.5L1e1,-2E-1", []Command{{"M", []float64{0.5, 0.5}}, {"L", []float64{10, -0.2}}}},
+		{"M 0 0 10 10 20 0", []Command{{"M", []float64{0, 0}}, {"L", []float64{10, 10, 20, 0}}}},
+		{"m 1 1 2 2", []Command{{"M", []float64{1, 1}}, {"L", []float64{3, 3}}}},
+		{"M0 0h10v5H0z", []Command{{"M", []float64{0, 0}}, {"H", []float64{10}}, {"V", []float64{5}}, {"H", []float64{0}}, {"Z", []float64{}}}},
+		{"M0 0a5 5 0 1010 0", []Command{{"M", []float64{0, 0}}, {"A", []float64{5, 5, 0, 1, 0, 10, 0}}}},
+		{"M 10 10 L 20 10 Z l 0 5", []Command{{"M", []float64{10, 10}}, {"L", []float64{20, 10}}, {"Z", []float64{}}, {"L", []float64{10, 15}}}},
+	}
+	for _, tc := range tests {
+		got := mustParseD(t, tc.d)
+		if !commandsEqual(got, tc.want) {
+			t.Errorf("%q: got %v expected %v", tc.d, got, tc.want)
+		}
+	}
+}
+
+func TestParseDCurves(t *testing.T) {
+	tests := []struct {
+		d    string
+		want []Command
+	}{
+		// the first control point of S is the reflection of the previous second one
+		{"M0 0C0 10 10 10 10 0S20-10 20 0", []Command{{"M", []float64{0, 0}}, {"C", []float64{0, 10, 10, 10, 10, 0}}, {"C", []float64{10, -10, 20, -10, 20, 0}}}},
+		// without a previous cubic curve it is the current point
+		{"M0 0s10 10 10 0", []Command{{"M", []float64{0, 0}}, {"C", []float64{0, 0, 10, 10, 10, 0}}}},
+		{"M0 0Q15 15 30 0", []Command{{"M", []float64{0, 0}}, {"C", []float64{10, 10, 20, 10, 30, 0}}}},
+		{"M0 0q15 15 30 0t30 0", []Command{{"M", []float64{0, 0}}, {"C", []float64{10, 10, 20, 10, 30, 0}}, {"C", []float64{40, -10, 50, -10, 60, 0}}}},
+		{"M0 0T30 0", []Command{{"M", []float64{0, 0}}, {"C", []float64{0, 0, 10, 0, 30, 0}}}},
+	}
+	for _, tc := range tests {
+		got := mustParseD(t, tc.d)
+		if !commandsEqual(got, tc.want) {
+			t.Errorf("%q: got %v expected %v", tc.d, got, tc.want)
+		}
+	}
+}
+
+func commandsEqual(a, b []Command) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Type != b[i].Type || len(a[i].Args) != len(b[i].Args) {
+			return false
+		}
+		for u := range a[i].Args {
+			if math.Abs(a[i].Args[u]-b[i].Args[u]) > 1e-9 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func mustParseD(t *testing.T, d string) []Command {
+	t.Helper()
+	commands, err := ParseD(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return commands
+}
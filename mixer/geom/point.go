@@ -0,0 +1,66 @@
+// Package geom holds the 2D geometry used by the mixer: points, beziers and
+// SVG path commands.
+package geom
+
+import "math"
+
+type Point struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+func (p Point) Add(p1 Point) Point {
+	return Point{
+		X: p.X + p1.X,
+		Y: p.Y + p1.Y,
+	}
+}
+
+func (p Point) Sub(p1 Point) Point {
+	return p.Add(Point{X: p1.X * -1, Y: p1.Y * -1})
+}
+
+func (p1 Point) Distance(p2 Point) float64 {
+	return math.Abs(p1.X-p2.X) + math.Abs(p1.Y-p2.Y)
+}
+
+// Length is the euclidean norm of p, p1.Sub(p2).Length() is the euclidean
+// distance where Distance is the manhattan one
+func (p Point) Length() float64 {
+	return math.Hypot(p.X, p.Y)
+}
+
+func (p Point) Rotate(angleDeg float64) Point {
+	angle := angleDeg * (math.Pi / 180)
+	return Point{
+		X: p.X*math.Cos(angle) - p.Y*math.Sin(angle),
+		Y: p.X*math.Sin(angle) + p.Y*math.Cos(angle),
+	}
+}
+
+func (p Point) Translate(t Point) Point {
+	return Point{
+		X: p.X + t.X,
+		Y: p.Y + t.Y,
+	}
+}
+
+func PointFindQuadrant(p Point) int {
+	switch {
+	case p.X < 0 && p.Y < 0:
+		return 1 // haut-gauche
+	case p.X < 0 && p.Y > 0:
+		return 2 // bas-gauche
+	case p.X > 0 && p.Y > 0:
+		return 3 // bas-droite
+	case p.X > 0 && p.Y < 0:
+		return 4 // haut-droite
+	default:
+		return 0 // sur un axe (X == 0 ou Y == 0)
+	}
+}
+
+type Rect struct {
+	TopLeft     Point `json:"topLeft"`
+	BottomRight Point `json:"bottomRight"`
+}
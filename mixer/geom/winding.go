@@ -0,0 +1,56 @@
+package geom
+
+import "math"
+
+// GetSubpathsFromCommands is GetBeziersFromCommands keeping the curves of
+// each subpath (started by a moveto) apart, commands must be absolute as
+// produced by ParseD
+func GetSubpathsFromCommands(commands []Command) [][]Bezier {
+	results := make([][]Bezier, 0)
+	start := 0
+	for i := 1; i <= len(commands); i++ {
+		if i < len(commands) && commands[i].Type != "M" {
+			continue
+		}
+		if beziers := GetBeziersFromCommands(commands[start:i]); len(beziers) > 0 {
+			results = append(results, beziers)
+		}
+		start = i
+	}
+	return results
+}
+
+// Gauss-Legendre nodes and weights on [0, 1], 3 nodes integrate the degree 5
+// polynomials of a cubic area exactly
+var areaNodes = [3]float64{0.5 - math.Sqrt(0.15), 0.5, 0.5 + math.Sqrt(0.15)}
+var areaWeights = [3]float64{5.0 / 18, 8.0 / 18, 5.0 / 18}
+
+// SignedArea returns the area enclosed by the curves, closed by a line from
+// the last point to the first one. With the SVG y axis pointing down it is
+// positive when the outline turns clockwise on screen.
+func SignedArea(beziers []Bezier) float64 {
+	if len(beziers) == 0 {
+		return 0
+	}
+	area := 0.0
+	for _, b := range beziers {
+		for i, t := range areaNodes {
+			p := b.At(t)
+			d := b.Derivative(t)
+			area += areaWeights[i] * (p.X*d.Y - p.Y*d.X)
+		}
+	}
+	first := beziers[0].P0
+	last := beziers[len(beziers)-1].P3
+	area += last.X*first.Y - first.X*last.Y
+	return area / 2
+}
+
+// OutwardNormal returns the unit normal of a tangent pointing out of an
+// outline of the given signed area
+func OutwardNormal(tangent Point, area float64) Point {
+	if area < 0 {
+		return Point{X: -tangent.Y, Y: tangent.X}
+	}
+	return Point{X: tangent.Y, Y: -tangent.X}
+}
@@ -0,0 +1,39 @@
+package geom
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSignedArea(t *testing.T) {
+	commands := mustParseD(t, "M 0 0 L 10 0 L 10 10 L 0 10 Z M 20 0 L 20 10 L 30 10 Z")
+	subpaths := GetSubpathsFromCommands(commands)
+	if len(subpaths) != 2 {
+		t.Fatalf("expected 2 subpaths, got %d", len(subpaths))
+	}
+	// clockwise on screen then counterclockwise
+	if got := SignedArea(subpaths[0]); math.Abs(got-100) > 1e-9 {
+		t.Errorf("got %f expected 100", got)
+	}
+	if got := SignedArea(subpaths[1]); math.Abs(got+50) > 1e-9 {
+		t.Errorf("got %f expected -50", got)
+	}
+
+	// a circle drawn with four arcs, left open: the closing line is implied.
+	// Arcs are approximated by beziers, close enough to the circle.
+	circle := mustParseD(t, "M 10 0 A 10 10 0 0 1 0 10 A 10 10 0 0 1 -10 0 A 10 10 0 0 1 0 -10 A 10 10 0 0 1 10 0")
+	if got := SignedArea(GetBeziersFromCommands(circle)); math.Abs(got-100*math.Pi) > 1 {
+		t.Errorf("got %f expected %f", got, 100*math.Pi)
+	}
+}
+
+func TestOutwardNormal(t *testing.T) {
+	// top edge of a square, going right
+	tangent := Point{X: 1, Y: 0}
+	if got := OutwardNormal(tangent, 100); !pointsClose(got, Point{X: 0, Y: -1}) {
+		t.Errorf("clockwise: got %v expected (0, -1)", got)
+	}
+	if got := OutwardNormal(tangent, -100); !pointsClose(got, Point{X: 0, Y: 1}) {
+		t.Errorf("counterclockwise: got %v expected (0, 1)", got)
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"tama/extract"
+	"tama/svgdoc"
+)
+
+// runLint checks the structure of the sheets, every problem is printed with
+// the layer it was found in
+func runLint(args []string, stdout, stderr io.Writer) int {
+	opts, err := parseLintFlags(args, stderr)
+	if err == flag.ErrHelp {
+		return 0
+	}
+	if err != nil {
+		return 2
+	}
+
+	failed := false
+	for _, input := range opts.inputs {
+		svg, err := svgdoc.Load(input)
+		if err != nil {
+			reportError(stderr, input, err)
+			failed = true
+			continue
+		}
+		errs := extract.Lint(svg, opts.extractOptions())
+		if len(errs) > 0 {
+			reportError(stderr, input, errs)
+		}
+		if len(errs.Failures()) > 0 {
+			failed = true
+		}
+		if opts.verbose {
+			fmt.Fprintf(stdout, "%s: %d layers, %d problems\n", input, len(svg.Groups), len(errs))
+		}
+	}
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+func parseLintFlags(args []string, stderr io.Writer) (options, error) {
+	var opts options
+	fs := flag.NewFlagSet("mixer lint", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: mixer lint [flags] [sheet.svg ...]\n\n")
+		fmt.Fprintf(stderr, "Checks the labels, anchors and frames of every layer of the sheets (default %s).\n\n", defaultInput)
+		fs.PrintDefaults()
+	}
+	fs.BoolVar(&opts.verbose, "v", false, "print what is being done")
+	fs.StringVar(&opts.partTypes, "part-types", "", partTypesUsage)
+	addColorFlags(fs, &opts)
+	if err := fs.Parse(args); err != nil {
+		return opts, err
+	}
+	return opts, useExtractFlags(fs, &opts, stderr)
+}
@@ -1,28 +1,343 @@
 package main
 
 import (
-	"encoding/xml"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+
+	"tama/compose"
+	"tama/export"
+	"tama/extract"
+	"tama/pet"
+	"tama/raster"
+	"tama/svgdoc"
 )
 
-func main() {
-	file, err := os.Open("svg/parts.svg")
+const defaultInput = "svg/parts.svg"
+
+type options struct {
+	inputs          []string
+	outDir          string
+	dryRun          bool
+	verbose         bool
+	partTypes       string
+	mirror          bool
+	mirrorTolerance float64
+	palettesFile    string
+	strictColors    bool
+	palettes        map[string]compose.Palette
+	types           pet.Registry
+	colors          pet.ColorSlots
+}
+
+const partTypesUsage = "JSON file listing the bodypart types, the built-in ones by default"
+
+// errBadFlag is a flag value rejected after parsing, it has been reported
+var errBadFlag = errors.New("bad flag value")
+
+// addExtractFlags registers the flags of every command extracting sheets,
+// dryRun tells what -dry-run does instead of writing
+func addExtractFlags(fs *flag.FlagSet, opts *options, dryRun string) {
+	fs.BoolVar(&opts.dryRun, "dry-run", false, dryRun)
+	fs.BoolVar(&opts.verbose, "v", false, "print what is being done")
+	fs.StringVar(&opts.partTypes, "part-types", "", partTypesUsage)
+	fs.BoolVar(&opts.mirror, "mirror", false, "derive arm2 and leg2 from arm1 and leg1 when a bodypart only has the first side")
+	fs.Float64Var(&opts.mirrorTolerance, "mirror-tolerance", 0.5, "with -mirror, report hand-drawn arm2 and leg2 farther than this from the mirrored arm1 and leg1")
+	addColorFlags(fs, opts)
+}
+
+// addColorFlags registers the flags mapping the sheet colors to palette slots
+func addColorFlags(fs *flag.FlagSet, opts *options) {
+	fs.StringVar(&opts.palettesFile, "palettes", "", "JSON palette manifest giving the slot of the sheet colors and named palettes")
+	fs.BoolVar(&opts.strictColors, "strict-colors", false, "fail on sheet colors without a palette slot instead of drawing them with the slots of unstyled paths")
+}
+
+// useExtractFlags loads the files named by the parsed flags and takes the
+// sheets from the arguments, problems are reported under the name of fs
+func useExtractFlags(fs *flag.FlagSet, opts *options, stderr io.Writer) error {
+	if err := usePartTypes(opts); err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", fs.Name(), err)
+		return errBadFlag
+	}
+	if err := usePalettes(opts); err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", fs.Name(), err)
+		return errBadFlag
+	}
+	opts.inputs = fs.Args()
+	if len(opts.inputs) == 0 {
+		opts.inputs = []string{defaultInput}
+	}
+	return nil
+}
+
+// usePartTypes reads the bodypart types of the -part-types flag, the
+// built-in ones without it
+func usePartTypes(opts *options) error {
+	opts.types = pet.DefaultRegistry
+	if opts.partTypes == "" {
+		return nil
+	}
+	file, err := os.Open(opts.partTypes)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	types, err := pet.LoadTypes(file)
+	if err == nil {
+		opts.types, err = pet.NewRegistry(types)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", opts.partTypes, err)
+	}
+	return nil
+}
+
+// extractOptions tells the extraction what the flags asked for
+func (opts options) extractOptions() extract.Options {
+	return extract.Options{Types: opts.types, Colors: opts.colors, StrictColors: opts.strictColors}
+}
+
+// usePalettes reads the palette manifest of the -palettes flag, if any: its
+// colors replace the built-in color slots and its palettes can be picked by
+// name
+func usePalettes(opts *options) error {
+	opts.colors = pet.DefaultColorSlots
+	if opts.palettesFile == "" {
+		return nil
+	}
+	file, err := os.Open(opts.palettesFile)
 	if err != nil {
-		panic(err)
+		return err
 	}
 	defer file.Close()
-	var svg SVG
-	decoder := xml.NewDecoder(file)
-	if err := decoder.Decode(&svg); err != nil {
-		panic(err)
-	}
-	bodies, bodyparts := Sort(svg)
-	bodiesGroups := GroupBodies(bodies)
-	bodypartsGroups := GroupBodyParts(bodyparts)
-	for _, group := range bodypartsGroups {
-		SaveBodyPartsToJSON("out/bodyparts/", group)
-	}
-	for _, group := range bodiesGroups {
-		SaveBodiesToJSON("out/bodies/", group)
+	manifest, err := compose.LoadManifest(file)
+	if err == nil && len(manifest.Colors) > 0 {
+		opts.colors, err = pet.NewColorSlots(manifest.Colors)
+	}
+	for name, palette := range manifest.Palettes {
+		if err != nil {
+			break
+		}
+		for _, slot := range pet.Slots {
+			if color, ok := palette.Color(slot); ok {
+				if _, err = raster.ParseColor(color); err != nil {
+					err = fmt.Errorf("palette %q: %s: %w", name, slot, err)
+					break
+				}
+			}
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", opts.palettesFile, err)
+	}
+	opts.palettes = manifest.Palettes
+	return nil
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run is the whole program, main only translates its result into an exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) > 0 {
+		switch args[0] {
+		case "generate":
+			return runGenerate(args[1:], stdout, stderr)
+		case "sprites":
+			return runSprites(args[1:], stdout, stderr)
+		case "preview":
+			return runPreview(args[1:], stdout, stderr)
+		case "lint":
+			return runLint(args[1:], stdout, stderr)
+		case "recolor":
+			return runRecolor(args[1:], stdout, stderr)
+		case "verify":
+			return runVerify(args[1:], stdout, stderr)
+		case "gen-types":
+			return runGenTypes(args[1:], stdout, stderr)
+		}
+	}
+	return runExtract(args, stdout, stderr)
+}
+
+// runExtract writes the bodies and bodyparts of the sheets as JSON
+func runExtract(args []string, stdout, stderr io.Writer) int {
+	opts, err := parseFlags(args, stderr)
+	if err == flag.ErrHelp {
+		return 0
+	}
+	if err != nil {
+		return 2
+	}
+
+	bodies, bodyparts, ok := extractAll(opts, stdout, stderr)
+	// every problem of every sheet has been reported, nothing is written
+	if !ok {
+		return 1
+	}
+
+	bodiesDir := filepath.Join(opts.outDir, "bodies")
+	bodypartsDir := filepath.Join(opts.outDir, "bodyparts")
+	for _, group := range pet.GroupBodyParts(bodyparts) {
+		if opts.verbose || opts.dryRun {
+			fmt.Fprintf(stdout, "%s (%d frames)\n", filepath.Join(bodypartsDir, string(group[0].Type)+"-"+group[0].Name+".json"), len(group))
+		}
+		if opts.dryRun {
+			continue
+		}
+		if err := export.SaveBodyPartsToJSON(bodypartsDir, group); err != nil {
+			fmt.Fprintf(stderr, "mixer: writing bodypart %s: %v\n", group[0].Name, err)
+			return 1
+		}
+	}
+	for _, group := range pet.GroupBodies(bodies) {
+		if opts.verbose || opts.dryRun {
+			fmt.Fprintf(stdout, "%s (%d frames)\n", filepath.Join(bodiesDir, group[0].Name+".json"), len(group))
+		}
+		if opts.dryRun {
+			continue
+		}
+		if err := export.SaveBodiesToJSON(bodiesDir, group); err != nil {
+			fmt.Fprintf(stderr, "mixer: writing body %s: %v\n", group[0].Name, err)
+			return 1
+		}
+	}
+	// the types the sheets were sorted with, for loaders: the renderer still
+	// draws its own list and doesn't read it
+	if opts.verbose || opts.dryRun {
+		fmt.Fprintf(stdout, "%s (%d types)\n", filepath.Join(opts.outDir, "types.json"), len(opts.types.Types()))
+	}
+	if !opts.dryRun {
+		if err := export.SaveTypesToJSON(opts.outDir, opts.types.Types()); err != nil {
+			fmt.Fprintf(stderr, "mixer: writing types: %v\n", err)
+			return 1
+		}
+	}
+	// the colors every slot can take, for loaders picking a palette
+	manifest := compose.NewManifest(bodies, bodyparts, opts.colors, opts.palettes)
+	if opts.verbose || opts.dryRun {
+		fmt.Fprintf(stdout, "%s (%d palettes)\n", filepath.Join(opts.outDir, "palette.json"), len(manifest.Palettes))
+	}
+	if !opts.dryRun {
+		if err := export.SavePaletteManifest(opts.outDir, manifest); err != nil {
+			fmt.Fprintf(stderr, "mixer: writing palettes: %v\n", err)
+			return 1
+		}
+	}
+	// the schema every file above follows, for verify and other tools
+	if opts.verbose || opts.dryRun {
+		fmt.Fprintln(stdout, filepath.Join(opts.outDir, "schema.json"))
+	}
+	if !opts.dryRun {
+		if err := export.SaveSchema(opts.outDir); err != nil {
+			fmt.Fprintf(stderr, "mixer: writing schema: %v\n", err)
+			return 1
+		}
+	}
+	// loaders start from the manifest, it is written once everything it lists is
+	assets := export.NewManifest(bodies, bodyparts, opts.types)
+	assets.Palette = "palette.json"
+	if opts.verbose || opts.dryRun {
+		fmt.Fprintf(stdout, "%s (%d bodies, %d bodyparts)\n", filepath.Join(opts.outDir, "manifest.json"), len(assets.Bodies), len(assets.Parts))
+	}
+	if !opts.dryRun {
+		if err := export.SaveManifest(opts.outDir, assets); err != nil {
+			fmt.Fprintf(stderr, "mixer: writing manifest: %v\n", err)
+			return 1
+		}
+	}
+	return 0
+}
+
+func parseFlags(args []string, stderr io.Writer) (options, error) {
+	var opts options
+	fs := flag.NewFlagSet("mixer", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: mixer [flags] [sheet.svg ...]\n")
+		fmt.Fprintf(stderr, "       mixer generate [flags] [sheet.svg ...]\n")
+		fmt.Fprintf(stderr, "       mixer sprites [flags] [sheet.svg ...]\n")
+		fmt.Fprintf(stderr, "       mixer preview -body name [flags] [sheet.svg ...]\n")
+		fmt.Fprintf(stderr, "       mixer lint [flags] [sheet.svg ...]\n")
+		fmt.Fprintf(stderr, "       mixer recolor [flags] [sheet.svg ...]\n")
+		fmt.Fprintf(stderr, "       mixer verify [flags] [dir ...]\n")
+		fmt.Fprintf(stderr, "       mixer gen-types [flags] [sheet.svg ...]\n\n")
+		fmt.Fprintf(stderr, "Extracts bodies and bodyparts from the given sheets (default %s).\n\n", defaultInput)
+		fs.PrintDefaults()
+	}
+	fs.StringVar(&opts.outDir, "out", "out", "output root, bodies and bodyparts are written in sub directories")
+	addExtractFlags(fs, &opts, "extract and list the files that would be written without writing them")
+	if err := fs.Parse(args); err != nil {
+		return opts, err
+	}
+	return opts, useExtractFlags(fs, &opts, stderr)
+}
+
+// extractAll merges the bodies and bodyparts of every sheet, problems are
+// reported on stderr and make it fail once every sheet has been read. With
+// -mirror, missing second sides are derived and diverging pairs are warned
+// about.
+func extractAll(opts options, stdout, stderr io.Writer) ([]pet.Body, []pet.BodyPart, bool) {
+	bodies := make([]pet.Body, 0)
+	bodyparts := make([]pet.BodyPart, 0)
+	failed := false
+	for _, input := range opts.inputs {
+		b, bp, err := extractFile(input, opts.extractOptions())
+		if err != nil {
+			reportError(stderr, input, err)
+			failed = true
+			continue
+		}
+		if opts.verbose {
+			fmt.Fprintf(stdout, "%s: %d bodies, %d bodyparts\n", input, len(b), len(bp))
+		}
+		bodies = append(bodies, b...)
+		bodyparts = append(bodyparts, bp...)
+	}
+	if failed || !opts.mirror {
+		return bodies, bodyparts, !failed
+	}
+
+	count := len(bodyparts)
+	bodyparts, reports, err := extract.MirrorPairs(bodyparts, opts.types, opts.mirrorTolerance)
+	if err != nil {
+		fmt.Fprintf(stderr, "mixer: mirroring: %v\n", err)
+		return nil, nil, false
+	}
+	if opts.verbose {
+		fmt.Fprintf(stdout, "%d bodyparts mirrored\n", len(bodyparts)-count)
+	}
+	for _, report := range reports {
+		fmt.Fprintf(stderr, "mixer: warning: %v\n", report)
+	}
+	return bodies, bodyparts, true
+}
+
+// extractFile decodes a sheet and sorts its layers into bodies and bodyparts
+func extractFile(filename string, opts extract.Options) ([]pet.Body, []pet.BodyPart, error) {
+	svg, err := svgdoc.Load(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+	return extract.Sort(svg, opts)
+}
+
+// reportError prints one line per problem found in a sheet
+func reportError(stderr io.Writer, filename string, err error) {
+	var errs extract.Errors
+	if !errors.As(err, &errs) {
+		fmt.Fprintf(stderr, "mixer: %v\n", err)
+		return
+	}
+	for _, e := range errs {
+		if e.Warning {
+			fmt.Fprintf(stderr, "mixer: %s: warning: %v\n", filename, e)
+		} else {
+			fmt.Fprintf(stderr, "mixer: %s: %v\n", filename, e)
+		}
 	}
 }
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testSheet = `<svg xmlns="http://www.w3.org/2000/svg" xmlns:inkscape="http://www.inkscape.org/namespaces/inkscape" viewBox="0 0 20 20">
+  <g inkscape:groupmode="layer" id="layer1" inkscape:label="ball-0">
+    <path id="path1" inkscape:label="body" style="fill:none;stroke:#000000;stroke-width:0.25" d="M 0 0 L 10 0 L 10 10 L 0 10 Z" />
+    <circle id="circle1" inkscape:label="eye" cx="5" cy="4" r="0.5" />
+  </g>
+  <g inkscape:groupmode="layer" id="layer2" inkscape:label="dot-0">
+    <path id="path2" style="fill:none;stroke:#000000;stroke-width:0.25" d="M -1 -1 L 1 -1 L 1 1 L -1 1 Z" />
+    <ellipse id="ellipse2" inkscape:label="eye" cx="0" cy="0" rx="0.5" ry="0.5" />
+  </g>
+</svg>
+`
+
+// badSheet has a layer without a frame
+const badSheet = `<svg xmlns="http://www.w3.org/2000/svg" xmlns:inkscape="http://www.inkscape.org/namespaces/inkscape" viewBox="0 0 20 20">
+  <g inkscape:groupmode="layer" id="layer1" inkscape:label="ball">
+    <path id="path1" style="fill:none;stroke:#000000" d="M 0 0 L 10 0 L 10 10 Z" />
+  </g>
+</svg>
+`
+
+// writeSheets writes the test sheets in a temporary directory
+func writeSheets(t *testing.T) (dir, good, bad string) {
+	t.Helper()
+	dir = t.TempDir()
+	good = filepath.Join(dir, "good.svg")
+	bad = filepath.Join(dir, "bad.svg")
+	if err := os.WriteFile(good, []byte(testSheet), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(bad, []byte(badSheet), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir, good, bad
+}
+
+func TestRun(t *testing.T) {
+	dir, good, bad := writeSheets(t)
+	out := filepath.Join(dir, "out")
+
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stdout string
+		stderr string
+	}{
+		{"help", []string{"-h"}, 0, "", "usage: mixer"},
+		{"unknown flag", []string{"-nope"}, 2, "", "flag provided but not defined"},
+		{"missing part types", []string{"-part-types", filepath.Join(dir, "none.json"), good}, 2, "", "mixer: open"},
+		{"dry run", []string{"-dry-run", "-out", out, good}, 0, filepath.Join(out, "bodies", "ball.json"), ""},
+		{"bad sheet", []string{"-out", out, bad}, 1, "", bad + `: layer "layer1"`},
+		{"missing sheet", []string{"-out", out, filepath.Join(dir, "none.svg")}, 1, "", "none.svg"},
+		{"generate", []string{"generate", "-dry-run", "-out", out, good}, 0, filepath.Join(out, "generated", "ball_0@eye=dot-0.svg"), ""},
+		{"generate bad flag", []string{"generate", "-format", "bmp", good}, 2, "", "mixer generate: unknown format"},
+		{"sprites", []string{"sprites", "-dry-run", "-out", out, good}, 0, filepath.Join(out, "sprites.png"), ""},
+		{"preview", []string{"preview", "-dry-run", "-body", "ball", "-out", out, good}, 0, filepath.Join(out, "preview", "ball@Walking.gif"), ""},
+		{"preview without body", []string{"preview", good}, 2, "", "-body is required"},
+		{"recolor", []string{"recolor", "-dry-run", "-out", out, good}, 0, filepath.Join(out, "recolored", "pet", "ball_0@eye=dot-0.svg"), ""},
+		{"gen-types", []string{"gen-types", "-dry-run", "-o", filepath.Join(out, "mixer.d.ts"), good}, 0, filepath.Join(out, "mixer.d.ts"), ""},
+		{"lint", []string{"lint", good}, 0, "", "warning"},
+		{"lint bad sheet", []string{"lint", bad}, 1, "", "layer1"},
+		{"verify missing", []string{"verify", filepath.Join(dir, "none")}, 1, "", ""},
+	}
+	for _, tc := range tests {
+		var stdout, stderr bytes.Buffer
+		if code := run(tc.args, &stdout, &stderr); code != tc.code {
+			t.Errorf("%s: got exit code %d expected %d, stderr:\n%s", tc.name, code, tc.code, stderr.String())
+		}
+		if !strings.Contains(stdout.String(), tc.stdout) {
+			t.Errorf("%s: got stdout:\n%s\nexpected it to contain %q", tc.name, stdout.String(), tc.stdout)
+		}
+		if !strings.Contains(stderr.String(), tc.stderr) {
+			t.Errorf("%s: got stderr:\n%s\nexpected it to contain %q", tc.name, stderr.String(), tc.stderr)
+		}
+	}
+	// nothing was written by the dry runs nor the failures
+	if _, err := os.Stat(out); !os.IsNotExist(err) {
+		t.Errorf("got %v expected %s not to exist", err, out)
+	}
+}
+
+func TestRunReportsEverySheet(t *testing.T) {
+	dir, good, bad := writeSheets(t)
+	other := filepath.Join(dir, "other.svg")
+	if err := os.WriteFile(other, []byte(badSheet), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(dir, "out")
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-out", out, bad, good, other}, &stdout, &stderr); code != 1 {
+		t.Errorf("got exit code %d expected 1", code)
+	}
+	for _, sheet := range []string{bad, other} {
+		if !strings.Contains(stderr.String(), sheet+": ") {
+			t.Errorf("got stderr:\n%s\nexpected a problem of %s", stderr.String(), sheet)
+		}
+	}
+	if strings.Contains(stderr.String(), good) {
+		t.Errorf("got stderr:\n%s\nexpected no problem of %s", stderr.String(), good)
+	}
+	if _, err := os.Stat(out); !os.IsNotExist(err) {
+		t.Errorf("got %v expected nothing written", err)
+	}
+}
+
+func TestRunExtract(t *testing.T) {
+	dir, good, _ := writeSheets(t)
+	out := filepath.Join(dir, "out")
+
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-out", out, good}, &stdout, &stderr); code != 0 {
+		t.Fatalf("got exit code %d expected 0, stderr:\n%s", code, stderr.String())
+	}
+	for _, name := range []string{"bodies/ball.json", "bodyparts/eye-dot.json", "types.json", "palette.json", "schema.json", "manifest.json"} {
+		if _, err := os.Stat(filepath.Join(out, filepath.FromSlash(name))); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+	// what was written is valid
+	if code := run([]string{"verify", out}, &stdout, &stderr); code != 0 {
+		t.Errorf("verify: got exit code %d expected 0, stderr:\n%s", code, stderr.String())
+	}
+}
@@ -0,0 +1,84 @@
+package pet
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+)
+
+// Palette slots name the colors of a pet, a renderer picks the actual colors,
+// see COLOR_PALETTE.pet
+const (
+	SlotStroke    = "stroke"
+	SlotFill1     = "fill1"
+	SlotFill2     = "fill2"
+	SlotAccent    = "accent"
+	SlotHighlight = "highlight"
+)
+
+// Slots are every palette slot, in the order palettes list them
+var Slots = []string{SlotStroke, SlotFill1, SlotFill2, SlotAccent, SlotHighlight}
+
+// ColorSlots map the colors drawn in a sheet to palette slots. Colors are
+// lower case "#rrggbb", slots one of Slots.
+type ColorSlots map[string]string
+
+// DefaultColorSlots map black details to the outline color and white ones to
+// highlights. Paths without fill are filled with fill1 or fill2 like the
+// renderer does.
+var DefaultColorSlots = ColorSlots{
+	"#000000": SlotStroke,
+	"#ffffff": SlotHighlight,
+}
+
+var ErrBadColorSlots = errors.New("bad color slots")
+
+var colorReg = regexp.MustCompile("^#[0-9a-f]{6}$")
+
+// NewColorSlots checks the colors and slots of the map and copies it
+func NewColorSlots(slots map[string]string) (ColorSlots, error) {
+	for color, slot := range slots {
+		if !colorReg.MatchString(color) {
+			return nil, fmt.Errorf("%w: color %q is not \"#rrggbb\"", ErrBadColorSlots, color)
+		}
+		if !slices.Contains(Slots, slot) {
+			return nil, fmt.Errorf("%w: unknown slot %q for %s", ErrBadColorSlots, slot, color)
+		}
+	}
+	return maps.Clone(slots), nil
+}
+
+// Slot returns the slot a sheet color is mapped to
+func (c ColorSlots) Slot(color string) (string, bool) {
+	slot, ok := c[color]
+	return slot, ok
+}
+
+// SubPath is one path of a body or a bodypart drawn with its own style. Fill
+// and Stroke are palette slots, empty when the path has none.
+type SubPath struct {
+	ID          string  `json:"id,omitempty"`
+	Path        string  `json:"path"`
+	Fill        string  `json:"fill,omitempty"`
+	Stroke      string  `json:"stroke,omitempty"`
+	StrokeWidth float64 `json:"strokeWidth"`
+	Opacity     float64 `json:"opacity"`
+	FillRule    string  `json:"fillRule"`
+}
+
+// UsedSlots lists the slots the sub-paths are drawn with, in the order of
+// Slots
+func UsedSlots(paths ...[]SubPath) []string {
+	used := make([]string, 0)
+	for _, slot := range Slots {
+		for _, sub := range slices.Concat(paths...) {
+			if sub.Fill == slot || sub.Stroke == slot {
+				used = append(used, slot)
+				break
+			}
+		}
+	}
+	return used
+}
@@ -0,0 +1,42 @@
+package pet
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestNewColorSlots(t *testing.T) {
+	if slot, ok := DefaultColorSlots.Slot("#000000"); !ok || slot != SlotStroke {
+		t.Errorf("got %q, %v expected black to be the stroke", slot, ok)
+	}
+	slots, err := NewColorSlots(map[string]string{"#e05050": SlotAccent})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if slot, ok := slots.Slot("#e05050"); !ok || slot != SlotAccent {
+		t.Errorf("got %q, %v expected an accent", slot, ok)
+	}
+	if _, ok := slots.Slot("#000000"); ok {
+		t.Errorf("black is not mapped")
+	}
+
+	for _, slots := range []map[string]string{
+		{"#E05050": SlotAccent},
+		{"red": SlotAccent},
+		{"#e05050": "shadow"},
+	} {
+		if _, err := NewColorSlots(slots); !errors.Is(err, ErrBadColorSlots) {
+			t.Errorf("%v: got %v expected %v", slots, err, ErrBadColorSlots)
+		}
+	}
+}
+
+func TestUsedSlots(t *testing.T) {
+	body := []SubPath{{Fill: SlotFill1, Stroke: SlotStroke}}
+	part := []SubPath{{Fill: SlotHighlight}, {Stroke: SlotStroke}}
+	want := []string{SlotStroke, SlotFill1, SlotHighlight}
+	if got := UsedSlots(body, part); !slices.Equal(got, want) {
+		t.Errorf("got %v expected %v", got, want)
+	}
+}
@@ -0,0 +1,156 @@
+// Package pet describes what the mixer extracts from an art sheet: bodies,
+// their anchor points, and the bodyparts pinned on them.
+package pet
+
+import (
+	"slices"
+	"strings"
+
+	"tama/geom"
+)
+
+type BodypartType string
+
+const (
+	BodypartType_Leg1  BodypartType = "leg1"
+	BodypartType_Leg2  BodypartType = "leg2"
+	BodypartType_Leg3  BodypartType = "leg3"
+	BodypartType_Mouth BodypartType = "mouth"
+	BodypartType_Eye   BodypartType = "eye"
+	BodypartType_Arm1  BodypartType = "arm1"
+	BodypartType_Arm2  BodypartType = "arm2"
+	BodypartType_Tail  BodypartType = "tail"
+	BodypartType_Ears  BodypartType = "ears"
+	BodypartType_Horns BodypartType = "horns"
+)
+
+// Side is the side of the body an anchor is on, as drawn on the sheet
+type Side string
+
+const (
+	SideNone  Side = ""
+	SideLeft  Side = "left"
+	SideRight Side = "right"
+)
+
+// ParseAnchorLabel reads the label of an anchor, a type optionally followed
+// by a side: "eye" or "eye.left"
+func ParseAnchorLabel(label string) (BodypartType, Side) {
+	name, side, ok := strings.Cut(label, ".")
+	if ok && (Side(side) == SideLeft || Side(side) == SideRight) {
+		return BodypartType(name), Side(side)
+	}
+	return BodypartType(label), SideNone
+}
+
+// Point is an anchor on a body, T is the rotation in degrees to apply to the
+// bodypart of the same Type pinned on it. Index tells the anchors of a type
+// apart, from left to right. Mirror flips the bodypart horizontally before it
+// is rotated, so a single bodypart fits both sides.
+type Point struct {
+	X      float64      `json:"x"`
+	Y      float64      `json:"y"`
+	T      float64      `json:"t"`
+	Type   BodypartType `json:"type"`
+	Side   Side         `json:"side,omitempty"`
+	Index  int          `json:"index"`
+	Mirror bool         `json:"mirror,omitempty"`
+}
+
+func (p Point) Position() geom.Point {
+	return geom.Point{X: p.X, Y: p.Y}
+}
+
+// Body is a frame of a body, Path joins every sub-path of Paths
+type Body struct {
+	Path        string    `json:"path"`
+	Paths       []SubPath `json:"paths"`
+	Points      []Point   `json:"points"`
+	Frame       int       `json:"frame"`
+	Name        string    `json:"name"`
+	BoundingBox geom.Rect `json:"boundingBox"`
+}
+
+// BodyPart is a frame of a bodypart, Path joins every sub-path of Paths
+type BodyPart struct {
+	Path        string       `json:"path"`
+	Paths       []SubPath    `json:"paths"`
+	Type        BodypartType `json:"type"`
+	Frame       int          `json:"frame"`
+	Name        string       `json:"name"`
+	BoundingBox geom.Rect    `json:"boundingBox"`
+}
+
+// GroupBodyParts sorts the bodyparts by name, type and frame, and splits them
+// into one group of frames per name and type
+func GroupBodyParts(bodyparts []BodyPart) [][]BodyPart {
+	if len(bodyparts) == 0 {
+		return [][]BodyPart{}
+	}
+
+	slices.SortFunc(bodyparts, func(a, b BodyPart) int {
+		if cmp := strings.Compare(a.Name, b.Name); cmp != 0 {
+			return cmp
+		}
+		if cmp := strings.Compare(string(a.Type), string(b.Type)); cmp != 0 {
+			return cmp
+		}
+		return a.Frame - b.Frame
+	})
+
+	results := make([][]BodyPart, 0)
+	currentGroup := make([]BodyPart, 0)
+
+	prevName := bodyparts[0].Name
+	prevType := bodyparts[0].Type
+
+	for i := range bodyparts {
+		bp := bodyparts[i]
+
+		if bp.Name != prevName || bp.Type != prevType {
+			if len(currentGroup) > 0 {
+				results = append(results, currentGroup)
+				currentGroup = make([]BodyPart, 0)
+			}
+			prevName = bp.Name
+			prevType = bp.Type
+		}
+
+		currentGroup = append(currentGroup, bp)
+	}
+
+	if len(currentGroup) > 0 {
+		results = append(results, currentGroup)
+	}
+
+	return results
+}
+
+// GroupBodies sorts the bodies by name and frame, and splits them into one
+// group of frames per name
+func GroupBodies(bodies []Body) [][]Body {
+	if len(bodies) == 0 {
+		return [][]Body{}
+	}
+	slices.SortFunc(bodies, func(a Body, b Body) int {
+		if cmp := strings.Compare(a.Name, b.Name); cmp != 0 {
+			return cmp
+		}
+		return a.Frame - b.Frame
+	})
+	results := make([][]Body, 0)
+	current := make([]Body, 0)
+	currentExpression := bodies[0].Name
+	for _, bodyparts := range bodies {
+		if bodyparts.Name != currentExpression {
+			currentExpression = bodyparts.Name
+			results = append(results, current)
+			current = make([]Body, 0)
+		}
+		current = append(current, bodyparts)
+	}
+	if len(current) > 0 {
+		results = append(results, current)
+	}
+	return results
+}
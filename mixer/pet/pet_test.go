@@ -0,0 +1,50 @@
+package pet
+
+import "testing"
+
+func TestGroupBodyPartsFrameOrder(t *testing.T) {
+	bodyparts := []BodyPart{
+		{Name: "stick", Type: BodypartType_Arm1, Frame: 1},
+		{Name: "stick", Type: BodypartType_Arm2, Frame: 0},
+		{Name: "stick", Type: BodypartType_Arm1, Frame: 0},
+		{Name: "paddle", Type: BodypartType_Arm1, Frame: 0},
+	}
+	groups := GroupBodyParts(bodyparts)
+	if len(groups) != 3 {
+		t.Fatalf("expected 3 groups, got %v", groups)
+	}
+	if got := groups[1]; got[0].Type != BodypartType_Arm1 || got[0].Frame != 0 || got[1].Frame != 1 {
+		t.Errorf("expected stick arm1 frames 0 then 1, got %v", got)
+	}
+}
+
+func TestGroupBodiesFrameOrder(t *testing.T) {
+	bodies := []Body{{Name: "mush", Frame: 2}, {Name: "ball", Frame: 0}, {Name: "mush", Frame: 0}, {Name: "mush", Frame: 1}}
+	groups := GroupBodies(bodies)
+	if len(groups) != 2 || len(groups[1]) != 3 {
+		t.Fatalf("expected ball and mush, got %v", groups)
+	}
+	for i, body := range groups[1] {
+		if body.Frame != i {
+			t.Errorf("frame %d of mush is %d", i, body.Frame)
+		}
+	}
+}
+
+func TestParseAnchorLabel(t *testing.T) {
+	tests := []struct {
+		label string
+		typ   BodypartType
+		side  Side
+	}{
+		{"eye", BodypartType_Eye, SideNone},
+		{"eye.left", BodypartType_Eye, SideLeft},
+		{"ears.right", BodypartType_Ears, SideRight},
+		{"eye.top", "eye.top", SideNone},
+	}
+	for _, tc := range tests {
+		if typ, side := ParseAnchorLabel(tc.label); typ != tc.typ || side != tc.side {
+			t.Errorf("%s: got %q %q expected %q %q", tc.label, typ, side, tc.typ, tc.side)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package pet
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// TypeInfo describes how the bodyparts of a type are extracted and drawn
+type TypeInfo struct {
+	Type BodypartType `json:"type"`
+	// Inside bodyparts are drawn over the body, on anchors left where they
+	// are drawn. The others are drawn behind it, their anchors are moved onto
+	// the body outline.
+	Inside bool `json:"inside"`
+	// Normalize turns the bodyparts so they point away from their root
+	// anchor, along the angle of the body anchor they are pinned on
+	Normalize bool `json:"normalize"`
+	// Pair is the type of the bodypart drawn on the other side of the body
+	Pair BodypartType `json:"pair,omitempty"`
+	// Mirror draws the same bodypart on both sides, flipped on the right
+	Mirror bool `json:"mirror,omitempty"`
+}
+
+// DefaultTypes are the bodypart types known by the renderer and the ones
+// sheets may already draw, in the order anchors are sorted
+var DefaultTypes = []TypeInfo{
+	{Type: BodypartType_Eye, Inside: true, Mirror: true},
+	{Type: BodypartType_Mouth, Inside: true},
+	{Type: BodypartType_Arm1, Normalize: true, Pair: BodypartType_Arm2},
+	{Type: BodypartType_Arm2, Normalize: true, Pair: BodypartType_Arm1},
+	{Type: BodypartType_Leg1, Normalize: true, Pair: BodypartType_Leg2},
+	{Type: BodypartType_Leg2, Normalize: true, Pair: BodypartType_Leg1},
+	{Type: BodypartType_Leg3, Normalize: true},
+	{Type: BodypartType_Tail, Normalize: true},
+	{Type: BodypartType_Ears, Normalize: true, Mirror: true},
+	{Type: BodypartType_Horns, Normalize: true, Mirror: true},
+}
+
+var ErrBadTypes = errors.New("bad bodypart types")
+
+// Registry is a list of bodypart types and the position of each type in it,
+// the zero value knows no type
+type Registry struct {
+	types []TypeInfo
+	index map[BodypartType]int
+}
+
+// DefaultRegistry holds DefaultTypes
+var DefaultRegistry = newRegistry(DefaultTypes)
+
+func newRegistry(types []TypeInfo) Registry {
+	r := Registry{types: slices.Clone(types), index: make(map[BodypartType]int, len(types))}
+	for i, info := range types {
+		r.index[info.Type] = i
+	}
+	return r
+}
+
+// NewRegistry checks the bodypart types, they must be unique and pairs must
+// name each other
+func NewRegistry(types []TypeInfo) (Registry, error) {
+	index := make(map[BodypartType]int, len(types))
+	for i, info := range types {
+		if info.Type == "" {
+			return Registry{}, fmt.Errorf("%w: type %d has no name", ErrBadTypes, i)
+		}
+		if _, ok := index[info.Type]; ok {
+			return Registry{}, fmt.Errorf("%w: %s is defined twice", ErrBadTypes, info.Type)
+		}
+		index[info.Type] = i
+	}
+	for _, info := range types {
+		if info.Pair == "" {
+			continue
+		}
+		if info.Mirror {
+			return Registry{}, fmt.Errorf("%w: %s has a pair for the other side, it can't be mirrored", ErrBadTypes, info.Type)
+		}
+		i, ok := index[info.Pair]
+		if !ok || info.Pair == info.Type || types[i].Pair != info.Type {
+			return Registry{}, fmt.Errorf("%w: %s and %s must be the pair of each other", ErrBadTypes, info.Type, info.Pair)
+		}
+		if types[i].Inside != info.Inside {
+			return Registry{}, fmt.Errorf("%w: %s and %s must be drawn on the same side of the body", ErrBadTypes, info.Type, info.Pair)
+		}
+	}
+	return newRegistry(types), nil
+}
+
+// Types returns the known bodypart types in order
+func (r Registry) Types() []TypeInfo {
+	return slices.Clone(r.types)
+}
+
+// LoadTypes reads a JSON list of TypeInfo, to be given to NewRegistry. The
+// list may also be the types of an object, as in the types file of the mixer.
+func LoadTypes(r io.Reader) ([]TypeInfo, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var file struct {
+		Version int        `json:"version"`
+		Types   []TypeInfo `json:"types"`
+	}
+	var v any = &file.Types
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		v = &file
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBadTypes, err)
+	}
+	if file.Types == nil {
+		file.Types = make([]TypeInfo, 0)
+	}
+	return file.Types, nil
+}
+
+// Info describes the type, unknown types are drawn behind the body and
+// normalized like arms and legs
+func (r Registry) Info(t BodypartType) (TypeInfo, bool) {
+	i, ok := r.index[t]
+	if !ok {
+		return TypeInfo{Type: t, Normalize: true}, false
+	}
+	return r.types[i], true
+}
+
+// Known reports if t is a registered type
+func (r Registry) Known(t BodypartType) bool {
+	_, ok := r.Info(t)
+	return ok
+}
+
+// Inside reports if bodyparts of this type are drawn over the body, like eyes
+// and mouths, rather than pinned on its outline and drawn behind it
+func (r Registry) Inside(t BodypartType) bool {
+	info, _ := r.Info(t)
+	return info.Inside
+}
+
+// Normalized reports if bodyparts of this type are turned to point away from
+// their root anchor
+func (r Registry) Normalized(t BodypartType) bool {
+	info, _ := r.Info(t)
+	return info.Normalize
+}
+
+// Mirrored reports if a single bodypart of this type is drawn on both sides
+func (r Registry) Mirrored(t BodypartType) bool {
+	info, _ := r.Info(t)
+	return info.Mirror
+}
+
+// Pair is the type of the bodypart drawn on the other side, arm2 for arm1
+func (r Registry) Pair(t BodypartType) (BodypartType, bool) {
+	info, _ := r.Info(t)
+	return info.Pair, info.Pair != ""
+}
+
+// Order is the position of the type in the registry, unknown types come last
+func (r Registry) Order(t BodypartType) int {
+	if i, ok := r.index[t]; ok {
+		return i
+	}
+	return len(r.types)
+}
+
+// Compare orders types as the registry does, for slices.SortFunc
+func (r Registry) Compare(a, b BodypartType) int {
+	return r.Order(a) - r.Order(b)
+}
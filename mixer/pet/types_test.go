@@ -0,0 +1,79 @@
+package pet
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDefaultTypes(t *testing.T) {
+	if _, err := NewRegistry(DefaultTypes); err != nil {
+		t.Fatal(err)
+	}
+	r := DefaultRegistry
+	if !r.Inside(BodypartType_Eye) || r.Normalized(BodypartType_Eye) {
+		t.Errorf("eyes are drawn over the body as they are")
+	}
+	if r.Inside(BodypartType_Tail) || !r.Normalized(BodypartType_Tail) {
+		t.Errorf("tails are drawn behind the body and normalized")
+	}
+	if other, ok := r.Pair(BodypartType_Leg2); !ok || other != BodypartType_Leg1 {
+		t.Errorf("got pair %q for leg2 expected leg1", other)
+	}
+	if _, ok := r.Pair(BodypartType_Leg3); ok {
+		t.Errorf("leg3 has no pair")
+	}
+	unknown := BodypartType("wings")
+	if r.Known(unknown) || r.Order(unknown) <= r.Order(BodypartType_Horns) {
+		t.Errorf("unknown types come after the known ones")
+	}
+	if r.Order(BodypartType_Mouth) >= r.Order(BodypartType_Arm1) || r.Order(BodypartType_Leg2) >= r.Order(BodypartType_Leg3) {
+		t.Errorf("types are not in the registry order")
+	}
+}
+
+func TestNewRegistry(t *testing.T) {
+	types, err := LoadTypes(strings.NewReader(`[
+		{"type": "fin1", "normalize": true, "pair": "fin2"},
+		{"type": "fin2", "normalize": true, "pair": "fin1"},
+		{"type": "eye", "inside": true}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewRegistry(types)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.Known("fin2") || r.Known(BodypartType_Arm1) {
+		t.Errorf("got types %v", r.Types())
+	}
+	if r.Order("eye") != 2 {
+		t.Errorf("got order %d for eye expected 2", r.Order("eye"))
+	}
+	if !DefaultRegistry.Known(BodypartType_Arm1) {
+		t.Errorf("the default registry changed")
+	}
+
+	bad := map[string][]TypeInfo{
+		"unnamed":   {{Inside: true}},
+		"twice":     {{Type: "eye"}, {Type: "eye"}},
+		"one sided": {{Type: "fin1", Pair: "fin2"}, {Type: "fin2"}},
+		"self":      {{Type: "fin1", Pair: "fin1"}},
+		"mixed":     {{Type: "fin1", Pair: "fin2"}, {Type: "fin2", Pair: "fin1", Inside: true}},
+		"mirrored":  {{Type: "fin1", Pair: "fin2", Mirror: true}, {Type: "fin2", Pair: "fin1"}},
+	}
+	for name, types := range bad {
+		if _, err := NewRegistry(types); !errors.Is(err, ErrBadTypes) {
+			t.Errorf("%s: got %v expected ErrBadTypes", name, err)
+		}
+	}
+	if _, err := LoadTypes(strings.NewReader(`[{"type": "eye", "front": true}]`)); !errors.Is(err, ErrBadTypes) {
+		t.Errorf("got %v expected ErrBadTypes for an unknown field", err)
+	}
+	// the types file written by the mixer
+	types, err = LoadTypes(strings.NewReader(`{"version": 1, "types": [{"type": "fin", "inside": false, "normalize": true}]}`))
+	if err != nil || len(types) != 1 || types[0].Type != "fin" {
+		t.Errorf("got %v, %v expected the fin type", types, err)
+	}
+}
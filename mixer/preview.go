@@ -0,0 +1,177 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"tama/compose"
+	"tama/pet"
+	"tama/preview"
+)
+
+type previewOptions struct {
+	options
+	body      string
+	name      string
+	animation preview.Animation
+	choices   map[pet.BodypartType]string
+	format    string
+	scale     float64
+	palette   compose.Palette
+}
+
+// runPreview writes an animated GIF or APNG of a body playing an animation
+func runPreview(args []string, stdout, stderr io.Writer) int {
+	opts, err := parsePreviewFlags(args, stderr)
+	if err == flag.ErrHelp {
+		return 0
+	}
+	if err != nil {
+		return 2
+	}
+
+	bodies, bodyparts, ok := extractAll(opts.options, stdout, stderr)
+	if !ok {
+		return 1
+	}
+	p, err := preview.NewPet(bodies, bodyparts, opts.types, opts.body, opts.choices)
+	if err != nil {
+		fmt.Fprintf(stderr, "mixer: %v\n", err)
+		return 1
+	}
+	frames, err := preview.Render(p, opts.animation, opts.scale, opts.palette)
+	if err != nil {
+		fmt.Fprintf(stderr, "mixer: %v\n", err)
+		return 1
+	}
+
+	ext := ".gif"
+	if opts.format == "apng" {
+		ext = ".png"
+	}
+	filename := filepath.Join(opts.outDir, "preview", opts.body+"@"+opts.name+ext)
+	if opts.verbose || opts.dryRun {
+		parts := make([]string, 0, len(p.Parts))
+		for t, frames := range p.Parts {
+			parts = append(parts, string(t)+"="+frames[0].Name)
+		}
+		slices.Sort(parts)
+		fmt.Fprintf(stdout, "%s (%d frames, %s)\n", filename, len(frames), strings.Join(parts, ","))
+	}
+	if opts.dryRun {
+		return 0
+	}
+	if err := writePreview(filename, frames, opts.format, opts.animation.Loop); err != nil {
+		fmt.Fprintf(stderr, "mixer: writing preview: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func writePreview(filename string, frames []preview.Frame, format string, loop bool) error {
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if format == "apng" {
+		return preview.WriteAPNG(file, frames, loop)
+	}
+	return preview.WriteGIF(file, frames, loop)
+}
+
+func parsePreviewFlags(args []string, stderr io.Writer) (previewOptions, error) {
+	var opts previewOptions
+	var parts, palette string
+	var speed, bodySpeed int
+	var loop bool
+	fs := flag.NewFlagSet("mixer preview", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: mixer preview -body name [flags] [sheet.svg ...]\n\n")
+		fmt.Fprintf(stderr, "Writes an animated GIF or APNG of a body playing one of the renderer animations (default sheet %s).\n\n", defaultInput)
+		fs.PrintDefaults()
+	}
+	fs.StringVar(&opts.outDir, "out", "out", "output root, previews are written in the preview sub directory")
+	addExtractFlags(fs, &opts.options, "render and list the file that would be written without writing it")
+	fs.StringVar(&opts.body, "body", "", "name of the body to animate")
+	fs.StringVar(&opts.name, "animation", "Walking", "renderer animation to play: "+strings.Join(animationNames(), ", "))
+	fs.StringVar(&parts, "parts", "", "comma separated type=name bodyparts to use, e.g. arm1=stick,eye=roundeye, others are picked by name")
+	fs.IntVar(&speed, "speed", 0, "ticks between two part frames, overrides the animation")
+	fs.IntVar(&bodySpeed, "body-speed", 0, "ticks between two body frames (0 for a still body), overrides the animation")
+	fs.BoolVar(&loop, "loop", true, "loop the animation, overrides the animation")
+	fs.StringVar(&opts.format, "format", "gif", "output format, gif or apng")
+	fs.Float64Var(&opts.scale, "scale", 4, "pixels per sheet unit")
+	fs.StringVar(&palette, "palette", "", paletteUsage)
+	err := fs.Parse(args)
+	if err != nil {
+		return opts, err
+	}
+
+	if opts.body == "" {
+		fmt.Fprintf(stderr, "mixer preview: -body is required\n")
+		return opts, errBadFlag
+	}
+	animation, ok := preview.Animations[opts.name]
+	if !ok {
+		fmt.Fprintf(stderr, "mixer preview: unknown animation %q\n", opts.name)
+		return opts, errBadFlag
+	}
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "speed":
+			animation.Speed = speed
+		case "body-speed":
+			animation.Body = bodySpeed
+		case "loop":
+			animation.Loop = loop
+		}
+	})
+	if animation.Speed < 0 || animation.Body < 0 {
+		fmt.Fprintf(stderr, "mixer preview: speeds can't be negative\n")
+		return opts, errBadFlag
+	}
+	opts.animation = animation
+	opts.choices = map[pet.BodypartType]string{}
+	for _, choice := range splitList(parts) {
+		t, name, ok := strings.Cut(choice, "=")
+		if !ok {
+			fmt.Fprintf(stderr, "mixer preview: bad part %q, expected type=name\n", choice)
+			return opts, errBadFlag
+		}
+		opts.choices[pet.BodypartType(t)] = name
+	}
+	if opts.format != "gif" && opts.format != "apng" {
+		fmt.Fprintf(stderr, "mixer preview: unknown format %q\n", opts.format)
+		return opts, errBadFlag
+	}
+	if opts.scale <= 0 {
+		fmt.Fprintf(stderr, "mixer preview: scale must be positive\n")
+		return opts, errBadFlag
+	}
+	if err := useExtractFlags(fs, &opts.options, stderr); err != nil {
+		return opts, err
+	}
+	if opts.palette, err = parsePalette(palette, opts.palettes); err != nil {
+		fmt.Fprintf(stderr, "mixer preview: palette: %v\n", err)
+		return opts, errBadFlag
+	}
+	return opts, nil
+}
+
+func animationNames() []string {
+	names := make([]string, 0, len(preview.Animations))
+	for name := range preview.Animations {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,211 @@
+// Package preview plays pet animations the way the renderer does and exports
+// them as animated GIF or APNG, to review them without a browser.
+package preview
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"slices"
+	"strings"
+	"time"
+
+	"tama/compose"
+	"tama/geom"
+	"tama/pet"
+	"tama/raster"
+)
+
+// Animation mirrors AnimationConfig in the renderer: Parts move to their next
+// frame every Speed ticks, the body every Body ticks (0 keeps it still).
+// Parts are the renderer slots: mouth, leg1, leg2, arm1, arm2, eye1, eye2.
+type Animation struct {
+	Parts []string
+	Speed int
+	Loop  bool
+	Body  int
+}
+
+// Animations are the PET_ANIMATIONS of the renderer
+var Animations = map[string]Animation{
+	"Stop":    {Parts: []string{}, Speed: 0, Loop: true, Body: 0},
+	"Idle":    {Parts: []string{}, Speed: 1, Loop: true, Body: 100},
+	"Walking": {Parts: []string{"leg1", "leg2", "arm1", "arm2"}, Speed: 10, Loop: true, Body: 20},
+	"Eating":  {Parts: []string{"mouth"}, Speed: 20, Loop: true, Body: 0},
+}
+
+// TickDuration is the time between two renderer ticks, one animation frame
+// of the browser
+const TickDuration = time.Second / 60
+
+// maxTicks bounds the length of a preview when the periods of the body and
+// the parts have no small common multiple
+const maxTicks = 60 * 60
+
+var ErrNoBody = errors.New("no body frame")
+
+// Pet is a body and the bodyparts pinned on it, with all their frames ordered
+type Pet struct {
+	Bodies []pet.Body
+	Parts  map[pet.BodypartType][]pet.BodyPart
+	// Types tells which bodyparts are drawn in front of the body
+	Types pet.Registry
+}
+
+// Pose is the frame index of the body and of each bodypart type
+type Pose struct {
+	Body  int
+	Parts map[pet.BodypartType]int
+}
+
+// Step is a pose held for some ticks
+type Step struct {
+	Pose  Pose
+	Ticks int
+}
+
+// slotType is the bodypart type drawn in a renderer slot, both eyes share
+// the eye type
+func slotType(slot string) pet.BodypartType {
+	if strings.HasPrefix(slot, "eye") {
+		return pet.BodypartType_Eye
+	}
+	return pet.BodypartType(slot)
+}
+
+// Timeline plays the animation on p tick after tick. Looping animations last
+// until every moving part is back to its first frame, the others until the
+// parts reach their last frame.
+func (a Animation) Timeline(p Pet) []Step {
+	moving := make([]pet.BodypartType, 0)
+	for _, slot := range a.Parts {
+		t := slotType(slot)
+		if len(p.Parts[t]) > 1 && !slices.Contains(moving, t) {
+			moving = append(moving, t)
+		}
+	}
+	if a.Speed <= 0 {
+		moving = moving[:0]
+	}
+	bodyFrames := len(p.Bodies)
+	animatedBody := a.Body > 0 && bodyFrames > 1
+
+	total := 1
+	if a.Loop {
+		if animatedBody {
+			total = lcm(total, a.Body*bodyFrames)
+		}
+		for _, t := range moving {
+			total = lcm(total, a.Speed*len(p.Parts[t]))
+		}
+	} else {
+		if animatedBody {
+			total = a.Body * bodyFrames
+		}
+		for _, t := range moving {
+			total = max(total, a.Speed*len(p.Parts[t]))
+		}
+	}
+	total = min(total, maxTicks)
+
+	pose := Pose{Parts: map[pet.BodypartType]int{}}
+	steps := []Step{{Pose: pose.clone(), Ticks: 0}}
+	for tick := 1; tick <= total; tick++ {
+		steps[len(steps)-1].Ticks++
+		if tick == total {
+			break
+		}
+		changed := false
+		if animatedBody && tick%a.Body == 0 {
+			pose.Body = (pose.Body + 1) % bodyFrames
+			changed = true
+		}
+		if len(moving) > 0 && tick%a.Speed == 0 {
+			for _, t := range moving {
+				next := pose.Parts[t] + 1
+				if next >= len(p.Parts[t]) {
+					next = len(p.Parts[t]) - 1
+					if a.Loop {
+						next = 0
+					}
+				}
+				if next != pose.Parts[t] {
+					pose.Parts[t] = next
+					changed = true
+				}
+			}
+		}
+		if changed {
+			steps = append(steps, Step{Pose: pose.clone()})
+		}
+	}
+	return steps
+}
+
+func (p Pose) clone() Pose {
+	parts := make(map[pet.BodypartType]int, len(p.Parts))
+	for t, frame := range p.Parts {
+		parts[t] = frame
+	}
+	return Pose{Body: p.Body, Parts: parts}
+}
+
+// Frame is a rendered step of the timeline
+type Frame struct {
+	Image    *image.NRGBA
+	Duration time.Duration
+}
+
+// Render draws every step of the timeline at scale. Frames share the same
+// size and origin, with a transparent margin of one pixel.
+func Render(p Pet, a Animation, scale float64, palette compose.Palette) ([]Frame, error) {
+	if len(p.Bodies) == 0 {
+		return nil, ErrNoBody
+	}
+	steps := a.Timeline(p)
+	compositions := make([]compose.Composition, 0, len(steps))
+	var bb geom.Rect
+	for i, step := range steps {
+		parts := make(map[pet.BodypartType]pet.BodyPart)
+		for t, frames := range p.Parts {
+			if len(frames) > 0 {
+				parts[t] = frames[step.Pose.Parts[t]]
+			}
+		}
+		c, err := compose.Compose(p.Bodies[step.Pose.Body], parts, p.Types)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			bb = c.BoundingBox
+		} else {
+			bb = bb.Union(c.BoundingBox)
+		}
+		compositions = append(compositions, c)
+	}
+
+	frames := make([]Frame, 0, len(steps))
+	for i, c := range compositions {
+		c.BoundingBox = bb
+		img, err := raster.Rasterize(c, scale, palette)
+		if err != nil {
+			return nil, fmt.Errorf("frame %d: %w", i, err)
+		}
+		framed := image.NewNRGBA(image.Rect(0, 0, img.Bounds().Dx()+2, img.Bounds().Dy()+2))
+		draw.Draw(framed, img.Bounds().Add(image.Pt(1, 1)), img, image.Point{}, draw.Src)
+		frames = append(frames, Frame{Image: framed, Duration: time.Duration(steps[i].Ticks) * TickDuration})
+	}
+	return frames, nil
+}
+
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+func lcm(a, b int) int {
+	return a / gcd(a, b) * b
+}
@@ -0,0 +1,107 @@
+package preview
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"io"
+)
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// WriteAPNG encodes the frames as an animated PNG, looping forever or played
+// once. Every frame is encoded as 8 bit RGBA, image/png would pick RGB for
+// the opaque ones and the frames of an APNG share one header.
+func WriteAPNG(w io.Writer, frames []Frame, loop bool) error {
+	if len(frames) == 0 {
+		return errors.New("apng: no frame")
+	}
+	if _, err := w.Write(pngSignature); err != nil {
+		return err
+	}
+	size := frames[0].Image.Bounds().Size()
+	sequence := uint32(0)
+	for i, frame := range frames {
+		bounds := frame.Image.Bounds()
+		if bounds.Size() != size {
+			return errors.New("apng: frames of different sizes")
+		}
+		data, err := encodeRGBA(frame.Image)
+		if err != nil {
+			return err
+		}
+		if i == 0 {
+			// 8 bits per channel, RGBA, no interlacing
+			header := append(be32(uint32(size.X), uint32(size.Y)), 8, 6, 0, 0, 0)
+			plays := uint32(1)
+			if loop {
+				plays = 0
+			}
+			if err := writeChunk(w, "IHDR", header); err != nil {
+				return err
+			}
+			if err := writeChunk(w, "acTL", be32(uint32(len(frames)), plays)); err != nil {
+				return err
+			}
+		}
+
+		control := be32(sequence, uint32(bounds.Dx()), uint32(bounds.Dy()), 0, 0)
+		// delay in milliseconds, dispose to background, replace the pixels
+		control = binary.BigEndian.AppendUint16(control, uint16(min(frame.Duration.Milliseconds(), 0xffff)))
+		control = binary.BigEndian.AppendUint16(control, 1000)
+		control = append(control, 1, 0)
+		if err := writeChunk(w, "fcTL", control); err != nil {
+			return err
+		}
+		sequence++
+
+		if i == 0 {
+			err = writeChunk(w, "IDAT", data)
+		} else {
+			err = writeChunk(w, "fdAT", append(be32(sequence), data...))
+			sequence++
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return writeChunk(w, "IEND", nil)
+}
+
+// encodeRGBA compresses the rows of img as PNG image data, unfiltered
+func encodeRGBA(img *image.NRGBA) ([]byte, error) {
+	var buf bytes.Buffer
+	z := zlib.NewWriter(&buf)
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		start := img.PixOffset(bounds.Min.X, y)
+		row := append([]byte{0}, img.Pix[start:start+4*bounds.Dx()]...)
+		if _, err := z.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	if err := z.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeChunk(w io.Writer, kind string, data []byte) error {
+	chunk := be32(uint32(len(data)))
+	chunk = append(chunk, kind...)
+	chunk = append(chunk, data...)
+	chunk = binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+	_, err := w.Write(chunk)
+	return err
+}
+
+func be32(values ...uint32) []byte {
+	b := make([]byte, 0, 4*len(values))
+	for _, v := range values {
+		b = binary.BigEndian.AppendUint32(b, v)
+	}
+	return b
+}
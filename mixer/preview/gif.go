@@ -0,0 +1,70 @@
+package preview
+
+import (
+	"image"
+	"image/color"
+	"image/gif"
+	"io"
+	"math"
+)
+
+// WriteGIF encodes the frames as an animated GIF, looping forever or played
+// once. GIF has no partial transparency, pixels are either drawn or not.
+func WriteGIF(w io.Writer, frames []Frame, loop bool) error {
+	palette, index := gifPalette(frames)
+	anim := &gif.GIF{LoopCount: -1}
+	if loop {
+		anim.LoopCount = 0
+	}
+	// the delay of a GIF frame is in hundredths of a second, the rounding
+	// error is carried to the next frame
+	elapsed, shown := 0.0, 0
+	for _, frame := range frames {
+		bounds := frame.Image.Bounds()
+		paletted := image.NewPaletted(bounds, palette)
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				paletted.SetColorIndex(x, y, index(frame.Image.NRGBAAt(x, y)))
+			}
+		}
+		elapsed += frame.Duration.Seconds() * 100
+		delay := int(math.Round(elapsed)) - shown
+		shown += delay
+		anim.Image = append(anim.Image, paletted)
+		anim.Delay = append(anim.Delay, delay)
+		anim.Disposal = append(anim.Disposal, gif.DisposalBackground)
+	}
+	return gif.EncodeAll(w, anim)
+}
+
+// gifPalette returns a palette of the colors used by the frames, transparent
+// first, and the function finding the palette index of a color. Colors are
+// made coarser until they fit in the 256 entries of a GIF palette.
+func gifPalette(frames []Frame) (color.Palette, func(color.NRGBA) uint8) {
+	for shift := uint(0); ; shift++ {
+		quantize := func(c color.NRGBA) color.NRGBA {
+			if c.A < 128 {
+				return color.NRGBA{}
+			}
+			mask := uint8(0xff << shift)
+			return color.NRGBA{R: c.R & mask, G: c.G & mask, B: c.B & mask, A: 255}
+		}
+		palette := color.Palette{color.NRGBA{}}
+		indexes := map[color.NRGBA]uint8{{}: 0}
+		for _, frame := range frames {
+			pix := frame.Image.Pix
+			for i := 0; i+3 < len(pix) && len(palette) <= 256; i += 4 {
+				c := quantize(color.NRGBA{R: pix[i], G: pix[i+1], B: pix[i+2], A: pix[i+3]})
+				if _, ok := indexes[c]; !ok {
+					indexes[c] = uint8(len(palette))
+					palette = append(palette, c)
+				}
+			}
+		}
+		if len(palette) <= 256 {
+			return palette, func(c color.NRGBA) uint8 {
+				return indexes[quantize(c)]
+			}
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package preview
+
+import (
+	"fmt"
+	"slices"
+
+	"tama/pet"
+)
+
+// closedEye is the eye the renderer only draws when blinking
+const closedEye = "CLOSED"
+
+// NewPet picks the frames of the named body and of a bodypart for each of
+// its anchor types. choices names the bodypart to use for a type, the others
+// are picked like the renderer does but without randomness: the first name,
+// arm2 and leg2 taking the name of arm1 and leg1 when they have one.
+func NewPet(bodies []pet.Body, bodyparts []pet.BodyPart, types pet.Registry, body string, choices map[pet.BodypartType]string) (Pet, error) {
+	p := Pet{Parts: map[pet.BodypartType][]pet.BodyPart{}, Types: types}
+	for _, group := range pet.GroupBodies(slices.Clone(bodies)) {
+		if group[0].Name == body {
+			p.Bodies = group
+		}
+	}
+	if len(p.Bodies) == 0 {
+		return Pet{}, fmt.Errorf("%w named %q", ErrNoBody, body)
+	}
+
+	groups := map[pet.BodypartType]map[string][]pet.BodyPart{}
+	for _, group := range pet.GroupBodyParts(slices.Clone(bodyparts)) {
+		t := group[0].Type
+		if groups[t] == nil {
+			groups[t] = map[string][]pet.BodyPart{}
+		}
+		groups[t][group[0].Name] = group
+	}
+	pick := func(t pet.BodypartType) string {
+		names := make([]string, 0)
+		for name := range groups[t] {
+			if !(t == pet.BodypartType_Eye && name == closedEye) {
+				names = append(names, name)
+			}
+		}
+		slices.Sort(names)
+		if len(names) == 0 {
+			return ""
+		}
+		return names[0]
+	}
+
+	anchored := make([]pet.BodypartType, 0)
+	for _, anchor := range p.Bodies[0].Points {
+		if !slices.Contains(anchored, anchor.Type) {
+			anchored = append(anchored, anchor.Type)
+		}
+	}
+	// arm1 and leg1 are chosen before arm2 and leg2 follow them
+	slices.SortStableFunc(anchored, types.Compare)
+	for t, name := range choices {
+		if _, ok := groups[t][name]; !ok {
+			return Pet{}, fmt.Errorf("no %s named %q", t, name)
+		}
+	}
+	chosen := map[pet.BodypartType]string{}
+	for _, t := range anchored {
+		name, ok := choices[t]
+		if other, paired := types.Pair(t); !ok && paired && types.Order(other) < types.Order(t) {
+			if _, exists := groups[t][chosen[other]]; exists {
+				name, ok = chosen[other], true
+			}
+		}
+		if !ok {
+			name = pick(t)
+		}
+		if name == "" {
+			continue
+		}
+		chosen[t] = name
+		p.Parts[t] = groups[t][name]
+	}
+	return p, nil
+}
@@ -0,0 +1,233 @@
+package preview
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"testing"
+
+	"tama/compose"
+	"tama/geom"
+	"tama/pet"
+)
+
+func testPet() Pet {
+	square := geom.Rect{TopLeft: geom.Point{X: 0, Y: 0}, BottomRight: geom.Point{X: 10, Y: 10}}
+	points := []pet.Point{{X: 5, Y: 10, T: 180, Type: pet.BodypartType_Leg1}, {X: 5, Y: 5, Type: pet.BodypartType_Mouth}}
+	return Pet{
+		Bodies: []pet.Body{
+			{Name: "box", Frame: 0, Path: "M 0 0 L 10 0 L 10 10 L 0 10 Z", BoundingBox: square, Points: points},
+			{Name: "box", Frame: 1, Path: "M 0 1 L 10 1 L 10 10 L 0 10 Z", BoundingBox: square, Points: points},
+		},
+		Parts: map[pet.BodypartType][]pet.BodyPart{
+			pet.BodypartType_Leg1: {
+				{Name: "stick", Type: pet.BodypartType_Leg1, Frame: 0, Path: "M -1 0 L 1 0 L 1 -3 L -1 -3 Z"},
+				{Name: "stick", Type: pet.BodypartType_Leg1, Frame: 1, Path: "M -1 0 L 1 0 L 1 -4 L -1 -4 Z"},
+				{Name: "stick", Type: pet.BodypartType_Leg1, Frame: 2, Path: "M -1 0 L 1 0 L 1 -5 L -1 -5 Z"},
+			},
+			pet.BodypartType_Mouth: {
+				{Name: "line", Type: pet.BodypartType_Mouth, Frame: 0, Path: "M -1 0 L 1 0"},
+			},
+		},
+		Types: pet.DefaultRegistry,
+	}
+}
+
+func TestTimeline(t *testing.T) {
+	type step struct{ body, leg, ticks int }
+	tests := []struct {
+		name      string
+		animation Animation
+		want      []step
+	}{
+		// the body cycles every 2 * 20 ticks and the leg every 3 * 10 ticks
+		{"walking", Animations["Walking"], []step{
+			{0, 0, 10}, {0, 1, 10}, {1, 2, 10}, {1, 0, 10}, {0, 1, 10}, {0, 2, 10},
+			{1, 0, 10}, {1, 1, 10}, {0, 2, 10}, {0, 0, 10}, {1, 1, 10}, {1, 2, 10},
+		}},
+		// the mouth has a single frame, only the still pose is left
+		{"eating", Animations["Eating"], []step{{0, 0, 1}}},
+		{"once", Animation{Parts: []string{"leg1"}, Speed: 5, Loop: false}, []step{{0, 0, 5}, {0, 1, 5}, {0, 2, 5}}},
+	}
+	for _, tc := range tests {
+		steps := tc.animation.Timeline(testPet())
+		if len(steps) != len(tc.want) {
+			t.Errorf("%s: got %d steps expected %d: %v", tc.name, len(steps), len(tc.want), steps)
+			continue
+		}
+		for i, w := range tc.want {
+			got := steps[i]
+			if got.Pose.Body != w.body || got.Pose.Parts[pet.BodypartType_Leg1] != w.leg || got.Ticks != w.ticks {
+				t.Errorf("%s: step %d is %v expected %v", tc.name, i, got, w)
+			}
+		}
+	}
+}
+
+func TestNewPet(t *testing.T) {
+	bodies := []pet.Body{{Name: "box", Points: []pet.Point{
+		{Type: pet.BodypartType_Eye}, {Type: pet.BodypartType_Arm1}, {Type: pet.BodypartType_Arm2},
+	}}}
+	bodyparts := []pet.BodyPart{
+		{Name: "CLOSED", Type: pet.BodypartType_Eye},
+		{Name: "round", Type: pet.BodypartType_Eye},
+		{Name: "wing", Type: pet.BodypartType_Arm1},
+		{Name: "stick", Type: pet.BodypartType_Arm1},
+		{Name: "stick", Type: pet.BodypartType_Arm2},
+		{Name: "wing", Type: pet.BodypartType_Arm2},
+		{Name: "noodle", Type: pet.BodypartType_Leg1},
+	}
+	p, err := NewPet(bodies, bodyparts, pet.DefaultRegistry, "box", map[pet.BodypartType]string{pet.BodypartType_Arm1: "wing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[pet.BodypartType]string{"eye": "round", "arm1": "wing", "arm2": "wing"}
+	if len(p.Parts) != len(want) {
+		t.Errorf("got parts %v expected %v", p.Parts, want)
+	}
+	for typ, name := range want {
+		if frames := p.Parts[typ]; len(frames) == 0 || frames[0].Name != name {
+			t.Errorf("%s: got %v expected %s", typ, frames, name)
+		}
+	}
+
+	if _, err := NewPet(bodies, bodyparts, pet.DefaultRegistry, "ball", nil); err == nil {
+		t.Errorf("expected an error for an unknown body")
+	}
+	if _, err := NewPet(bodies, bodyparts, pet.DefaultRegistry, "box", map[pet.BodypartType]string{pet.BodypartType_Eye: "nope"}); err == nil {
+		t.Errorf("expected an error for an unknown bodypart")
+	}
+}
+
+func TestWriteGIF(t *testing.T) {
+	frames, err := Render(testPet(), Animation{Parts: []string{"leg1"}, Speed: 5, Loop: false}, 2, compose.PetPalette)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := WriteGIF(&buf, frames, false); err != nil {
+		t.Fatal(err)
+	}
+	anim, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(anim.Image) != 3 || anim.LoopCount != -1 {
+		t.Fatalf("got %d frames looping %d, expected 3 frames played once", len(anim.Image), anim.LoopCount)
+	}
+	// 5 ticks are 8.33 hundredths of a second, the rounding is spread
+	if anim.Delay[0]+anim.Delay[1]+anim.Delay[2] != 25 {
+		t.Errorf("got delays %v expected a total of 25", anim.Delay)
+	}
+	if anim.Image[0].Bounds() != frames[0].Image.Bounds() {
+		t.Errorf("got size %v expected %v", anim.Image[0].Bounds(), frames[0].Image.Bounds())
+	}
+}
+
+func TestWriteAPNG(t *testing.T) {
+	frames, err := Render(testPet(), Animations["Walking"], 2, compose.PetPalette)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := WriteAPNG(&buf, frames, true); err != nil {
+		t.Fatal(err)
+	}
+
+	// decoders without animation support show the first frame
+	img, err := png.Decode(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds() != frames[0].Image.Bounds() {
+		t.Errorf("got size %v expected %v", img.Bounds(), frames[0].Image.Bounds())
+	}
+
+	chunks, err := readChunks(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := binary.BigEndian.Uint32(chunks["acTL"][0]); int(got) != len(frames) {
+		t.Errorf("acTL has %d frames expected %d", got, len(frames))
+	}
+	if len(chunks["fcTL"]) != len(frames) || len(chunks["fdAT"]) < len(frames)-1 {
+		t.Errorf("got %d fcTL and %d fdAT chunks for %d frames", len(chunks["fcTL"]), len(chunks["fdAT"]), len(frames))
+	}
+	delay := binary.BigEndian.Uint16(chunks["fcTL"][0][20:])
+	if want := (10 * TickDuration).Milliseconds(); int64(delay) != want {
+		t.Errorf("got delay %dms expected %dms", delay, want)
+	}
+}
+
+func TestWriteAPNGOpaqueFrame(t *testing.T) {
+	// image/png alone writes the opaque frame as RGB and the other as RGBA
+	opaque := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	for i := range opaque.Pix {
+		opaque.Pix[i] = 0xff
+	}
+	transparent := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	transparent.SetNRGBA(1, 1, color.NRGBA{R: 0xff, A: 0x80})
+	frames := []Frame{{Image: opaque, Duration: TickDuration}, {Image: transparent, Duration: TickDuration}}
+	var buf bytes.Buffer
+	if err := WriteAPNG(&buf, frames, true); err != nil {
+		t.Fatal(err)
+	}
+
+	chunks, err := readChunks(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chunks["fdAT"]) != 1 {
+		t.Fatalf("got %d fdAT chunks expected 1", len(chunks["fdAT"]))
+	}
+	// every frame decodes as a PNG of its own with the shared header
+	for i, data := range [][]byte{chunks["IDAT"][0], chunks["fdAT"][0][4:]} {
+		var still bytes.Buffer
+		still.Write(pngSignature)
+		for _, c := range []struct {
+			kind string
+			data []byte
+		}{{"IHDR", chunks["IHDR"][0]}, {"IDAT", data}, {"IEND", nil}} {
+			if err := writeChunk(&still, c.kind, c.data); err != nil {
+				t.Fatal(err)
+			}
+		}
+		img, err := png.Decode(&still)
+		if err != nil {
+			t.Fatalf("frame %d: %v", i, err)
+		}
+		for _, p := range []image.Point{{0, 0}, {1, 1}} {
+			if got, want := color.NRGBAModel.Convert(img.At(p.X, p.Y)), frames[i].Image.At(p.X, p.Y); got != want {
+				t.Errorf("frame %d at %v: got %v expected %v", i, p, got, want)
+			}
+		}
+	}
+}
+
+var errBadPNG = errors.New("apng: unexpected png encoding")
+
+// readChunks returns the data of the chunks of an encoded PNG by type
+func readChunks(data []byte) (map[string][][]byte, error) {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return nil, errBadPNG
+	}
+	chunks := make(map[string][][]byte)
+	data = data[len(pngSignature):]
+	for len(data) >= 12 {
+		length := int(binary.BigEndian.Uint32(data))
+		if len(data) < 12+length {
+			return nil, errBadPNG
+		}
+		kind := string(data[4:8])
+		chunks[kind] = append(chunks[kind], data[8:8+length])
+		data = data[12+length:]
+	}
+	if len(chunks["IHDR"]) != 1 || len(chunks["IDAT"]) == 0 {
+		return nil, errBadPNG
+	}
+	return chunks, nil
+}
@@ -0,0 +1,151 @@
+package raster
+
+import (
+	"math"
+	"slices"
+
+	"tama/geom"
+)
+
+// samples per pixel side, a pixel is covered by samples*samples points
+const samples = 4
+
+// mask tells which sample points of an image a shape covers
+type mask struct {
+	width, height int // in samples
+	covered       []bool
+}
+
+func newMask(width, height int) *mask {
+	return &mask{
+		width:   width * samples,
+		height:  height * samples,
+		covered: make([]bool, width*height*samples*samples),
+	}
+}
+
+// coverage is the part of the pixel (x, y) covered, from 0 to 1
+func (m *mask) coverage(x, y int) float64 {
+	count := 0
+	for sy := y * samples; sy < (y+1)*samples; sy++ {
+		for sx := x * samples; sx < (x+1)*samples; sx++ {
+			if m.covered[sy*m.width+sx] {
+				count++
+			}
+		}
+	}
+	return float64(count) / (samples * samples)
+}
+
+// fill covers the inside of the polygons with the nonzero rule, like the
+// canvas fill of the renderer, or the evenodd one. Points are in pixels.
+func (m *mask) fill(polygons [][]geom.Point, evenOdd bool) {
+	type crossing struct {
+		x   float64
+		dir int
+	}
+	crossings := make([]crossing, 0)
+	for sy := 0; sy < m.height; sy++ {
+		y := (float64(sy) + 0.5) / samples
+		crossings = crossings[:0]
+		for _, polygon := range polygons {
+			for i := range polygon {
+				a, b := polygon[i], polygon[(i+1)%len(polygon)]
+				if a.Y == b.Y || y < math.Min(a.Y, b.Y) || y >= math.Max(a.Y, b.Y) {
+					continue
+				}
+				dir := 1
+				if b.Y < a.Y {
+					dir = -1
+				}
+				crossings = append(crossings, crossing{x: a.X + (y-a.Y)*(b.X-a.X)/(b.Y-a.Y), dir: dir})
+			}
+		}
+		slices.SortFunc(crossings, func(a, b crossing) int {
+			return cmpFloat(a.x, b.x)
+		})
+		winding := 0
+		for i, c := range crossings {
+			winding += c.dir
+			if winding == 0 || evenOdd && winding%2 == 0 || i+1 == len(crossings) {
+				continue
+			}
+			// samples whose center is between the two crossings are inside
+			from := int(math.Ceil(c.x*samples - 0.5))
+			to := int(math.Ceil(crossings[i+1].x*samples - 0.5))
+			for sx := max(from, 0); sx < min(to, m.width); sx++ {
+				m.covered[sy*m.width+sx] = true
+			}
+		}
+	}
+}
+
+// stroke covers the samples closer than width/2 to the polylines. Points and
+// width are in pixels.
+func (m *mask) stroke(polylines [][]geom.Point, width float64) {
+	half := width / 2
+	for _, polyline := range polylines {
+		for i := 0; i+1 < len(polyline); i++ {
+			a, b := polyline[i], polyline[i+1]
+			minX := int(math.Floor((math.Min(a.X, b.X) - half) * samples))
+			maxX := int(math.Ceil((math.Max(a.X, b.X) + half) * samples))
+			minY := int(math.Floor((math.Min(a.Y, b.Y) - half) * samples))
+			maxY := int(math.Ceil((math.Max(a.Y, b.Y) + half) * samples))
+			for sy := max(minY, 0); sy < min(maxY, m.height); sy++ {
+				for sx := max(minX, 0); sx < min(maxX, m.width); sx++ {
+					p := geom.Point{X: (float64(sx) + 0.5) / samples, Y: (float64(sy) + 0.5) / samples}
+					if segmentDistance(p, a, b) <= half {
+						m.covered[sy*m.width+sx] = true
+					}
+				}
+			}
+		}
+	}
+}
+
+func segmentDistance(p, a, b geom.Point) float64 {
+	ab := b.Sub(a)
+	length := ab.X*ab.X + ab.Y*ab.Y
+	if length == 0 {
+		return p.Sub(a).Length()
+	}
+	t := ((p.X-a.X)*ab.X + (p.Y-a.Y)*ab.Y) / length
+	t = math.Max(0, math.Min(1, t))
+	return p.Sub(geom.Point{X: a.X + t*ab.X, Y: a.Y + t*ab.Y}).Length()
+}
+
+func cmpFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// flatten turns path data into polylines, one per subpath, moved by m. Curves
+// are cut in pieces of about tolerance pixels.
+func flatten(d string, m geom.Matrix, tolerance float64) ([][]geom.Point, error) {
+	if d == "" {
+		return nil, nil
+	}
+	commands, err := geom.ParseD(d)
+	if err != nil {
+		return nil, err
+	}
+	commands = m.TransformCommands(commands)
+	polylines := make([][]geom.Point, 0)
+	for _, subpath := range geom.GetSubpathsFromCommands(commands) {
+		polyline := []geom.Point{subpath[0].P0}
+		for _, b := range subpath {
+			length := b.P1.Sub(b.P0).Length() + b.P2.Sub(b.P1).Length() + b.P3.Sub(b.P2).Length()
+			pieces := min(max(int(math.Ceil(length/tolerance)), 1), 64)
+			for i := 1; i <= pieces; i++ {
+				polyline = append(polyline, b.At(float64(i)/float64(pieces)))
+			}
+		}
+		polylines = append(polylines, polyline)
+	}
+	return polylines, nil
+}
@@ -0,0 +1,187 @@
+// Package raster draws composed pets into bitmaps, the same way the renderer
+// paints them on its canvas, without cgo nor external dependencies.
+package raster
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"tama/compose"
+	"tama/geom"
+	"tama/pet"
+)
+
+var ErrBadColor = errors.New(`color must be "#rgb" or "#rrggbb"`)
+
+// Rasterize draws the composition scale pixels per unit, the image covers its
+// bounding box and outlines. Layers are painted like the renderer does: out
+// parts, the body erasing what is under it, the body, then in parts. Each
+// layer paints its fills then its outlines, with the colors of their slots.
+func Rasterize(c compose.Composition, scale float64, palette compose.Palette) (*image.NRGBA, error) {
+	if err := palette.Check(c.Slots()); err != nil {
+		return nil, err
+	}
+	colors := make(map[string]color.NRGBA)
+	for _, slot := range c.Slots() {
+		hex, _ := palette.Color(slot)
+		col, err := ParseColor(hex)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", slot, err)
+		}
+		colors[slot] = col
+	}
+
+	box := c.ViewBox()
+	width := int(math.Ceil(box.Width() * scale))
+	height := int(math.Ceil(box.Height() * scale))
+	origin := Origin(c, scale)
+	m := geom.TranslateMatrix(origin.X, origin.Y).Multiply(geom.ScaleMatrix(scale, scale))
+
+	canvas := newCanvas(width, height)
+	layers := []struct {
+		name  string
+		paths []pet.SubPath
+		erase bool
+	}{
+		{"out", c.Out, false},
+		{"body", c.Body, true},
+		{"in", c.In, false},
+	}
+	for _, layer := range layers {
+		polylines := make([][][]geom.Point, len(layer.paths))
+		for i, sub := range layer.paths {
+			var err error
+			if polylines[i], err = flatten(sub.Path, m, 0.5); err != nil {
+				return nil, fmt.Errorf("%s: %w", layer.name, err)
+			}
+		}
+		if layer.erase {
+			under := newMask(width, height)
+			for i, sub := range layer.paths {
+				under.fill(polylines[i], sub.FillRule == "evenodd")
+			}
+			canvas.erase(under)
+		}
+		for i, sub := range layer.paths {
+			if sub.Fill == "" {
+				continue
+			}
+			fill := newMask(width, height)
+			fill.fill(polylines[i], sub.FillRule == "evenodd")
+			canvas.paint(fill, colors[sub.Fill], opacity(sub))
+		}
+		for i, sub := range layer.paths {
+			if sub.Stroke == "" {
+				continue
+			}
+			outline := newMask(width, height)
+			outline.stroke(polylines[i], compose.OutlineWidth(sub)*scale)
+			canvas.paint(outline, colors[sub.Stroke], opacity(sub))
+		}
+	}
+	return canvas.image(), nil
+}
+
+func opacity(sub pet.SubPath) float64 {
+	return min(max(sub.Opacity, 0), 1)
+}
+
+// Origin is the position in pixels of the point (0, 0) in the image drawn by
+// Rasterize
+func Origin(c compose.Composition, scale float64) geom.Point {
+	box := c.ViewBox()
+	return geom.Point{X: -box.TopLeft.X * scale, Y: -box.TopLeft.Y * scale}
+}
+
+// WritePNG rasterizes the composition and encodes it as PNG
+func WritePNG(w io.Writer, c compose.Composition, scale float64, palette compose.Palette) error {
+	img, err := Rasterize(c, scale, palette)
+	if err != nil {
+		return err
+	}
+	return png.Encode(w, img)
+}
+
+// ParseColor reads a CSS hexadecimal color
+func ParseColor(s string) (color.NRGBA, error) {
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 || !strings.HasPrefix(s, "#") {
+		return color.NRGBA{}, fmt.Errorf("%w: %q", ErrBadColor, s)
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.NRGBA{}, fmt.Errorf("%w: %q", ErrBadColor, s)
+	}
+	return color.NRGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 255}, nil
+}
+
+// canvas holds premultiplied colors as floats from 0 to 1
+type canvas struct {
+	width, height int
+	pixels        [][4]float64
+}
+
+func newCanvas(width, height int) *canvas {
+	return &canvas{width: width, height: height, pixels: make([][4]float64, width*height)}
+}
+
+// paint draws the color over the canvas where the mask covers it, alpha is
+// the opacity of the color
+func (c *canvas) paint(m *mask, col color.NRGBA, alpha float64) {
+	src := [4]float64{float64(col.R) / 255 * alpha, float64(col.G) / 255 * alpha, float64(col.B) / 255 * alpha, alpha}
+	for y := 0; y < c.height; y++ {
+		for x := 0; x < c.width; x++ {
+			coverage := m.coverage(x, y)
+			if coverage == 0 {
+				continue
+			}
+			p := &c.pixels[y*c.width+x]
+			for i := range p {
+				p[i] = src[i]*coverage + p[i]*(1-alpha*coverage)
+			}
+		}
+	}
+}
+
+// erase clears the canvas where the mask covers it, the destination-out
+// composite operation
+func (c *canvas) erase(m *mask) {
+	for y := 0; y < c.height; y++ {
+		for x := 0; x < c.width; x++ {
+			coverage := m.coverage(x, y)
+			p := &c.pixels[y*c.width+x]
+			for i := range p {
+				p[i] *= 1 - coverage
+			}
+		}
+	}
+}
+
+func (c *canvas) image() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, c.width, c.height))
+	for y := 0; y < c.height; y++ {
+		for x := 0; x < c.width; x++ {
+			p := c.pixels[y*c.width+x]
+			if p[3] == 0 {
+				continue
+			}
+			img.SetNRGBA(x, y, color.NRGBA{
+				R: uint8(math.Round(p[0] / p[3] * 255)),
+				G: uint8(math.Round(p[1] / p[3] * 255)),
+				B: uint8(math.Round(p[2] / p[3] * 255)),
+				A: uint8(math.Round(p[3] * 255)),
+			})
+		}
+	}
+	return img
+}
@@ -0,0 +1,108 @@
+package raster
+
+import (
+	"errors"
+	"image/color"
+	"testing"
+
+	"tama/compose"
+	"tama/geom"
+	"tama/pet"
+)
+
+// outlined fills each d with the slot
+func outlined(fill string, ds ...string) []pet.SubPath {
+	paths := make([]pet.SubPath, 0, len(ds))
+	for _, d := range ds {
+		paths = append(paths, pet.SubPath{Path: d, Fill: fill, Stroke: pet.SlotStroke, Opacity: 1})
+	}
+	return paths
+}
+
+func TestRasterize(t *testing.T) {
+	c := compose.Composition{
+		Body: outlined(pet.SlotFill1, "M 0 0 L 10 0 L 10 10 L 0 10 Z"),
+		// one part hidden under the body, one sticking out on the right
+		Out:         outlined(pet.SlotFill2, "M 2 2 L 4 2 L 4 4 L 2 4 Z", "M 10 4 L 14 4 L 14 6 L 10 6 Z"),
+		In:          outlined(pet.SlotFill2, "M 6 6 L 8 6 L 8 8 L 6 8 Z"),
+		BoundingBox: geom.Rect{TopLeft: geom.Point{X: 0, Y: 0}, BottomRight: geom.Point{X: 14, Y: 10}},
+	}
+	img, err := Rasterize(c, 4, compose.PetPalette)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := img.Bounds().Size(); got.X != 60 || got.Y != 44 {
+		t.Fatalf("got size %v expected 60x44", got)
+	}
+	stroke := color.NRGBA{R: 0x00, G: 0x4c, B: 0x84, A: 255}
+	body := color.NRGBA{R: 0xff, G: 0xf7, B: 0x9c, A: 255}
+	parts := color.NRGBA{R: 0x41, G: 0x92, B: 0xcd, A: 255}
+	// pixel coordinates are (unit + 0.5) * 4
+	tests := []struct {
+		name string
+		x, y int
+		want color.NRGBA
+	}{
+		{"body", 22, 22, body},
+		{"hidden out part", 14, 14, body},
+		{"visible out part", 50, 22, parts},
+		{"in part", 30, 30, parts},
+		{"body outline", 2, 22, stroke},
+		{"outside", 58, 2, color.NRGBA{}},
+	}
+	for _, tc := range tests {
+		if got := img.NRGBAAt(tc.x, tc.y); got != tc.want {
+			t.Errorf("%s: got %v expected %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestRasterizeSubPaths(t *testing.T) {
+	c := compose.Composition{
+		Body: []pet.SubPath{
+			{Path: "M 0 0 L 10 0 L 10 10 L 0 10 Z", Fill: pet.SlotFill1, Opacity: 1},
+			// a ring, its hole shows the body
+			{Path: "M 2 2 L 8 2 L 8 8 L 2 8 Z M 4 4 L 6 4 L 6 6 L 4 6 Z", Fill: pet.SlotAccent, Opacity: 1, FillRule: "evenodd"},
+			{Path: "M 0 0 L 10 0 L 10 2 L 0 2 Z", Fill: pet.SlotHighlight, Opacity: 0.5},
+		},
+		BoundingBox: geom.Rect{TopLeft: geom.Point{X: 0, Y: 0}, BottomRight: geom.Point{X: 10, Y: 10}},
+	}
+	palette := compose.Palette{Stroke: "#000000", BodyFill: "#ff0000", PartsFill: "#00ff00", Accent: "#0000ff", Highlight: "#ffffff"}
+	img, err := Rasterize(c, 4, palette)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		x, y int
+		want color.NRGBA
+	}{
+		{"ring", 14, 14, color.NRGBA{B: 255, A: 255}},
+		{"hole", 22, 22, color.NRGBA{R: 255, A: 255}},
+		{"half highlight", 22, 6, color.NRGBA{R: 255, G: 128, B: 128, A: 255}},
+	}
+	for _, tc := range tests {
+		if got := img.NRGBAAt(tc.x, tc.y); got != tc.want {
+			t.Errorf("%s: got %v expected %v", tc.name, got, tc.want)
+		}
+	}
+
+	palette.Accent = ""
+	if _, err := Rasterize(c, 4, palette); !errors.Is(err, compose.ErrMissingSlot) {
+		t.Errorf("got %v expected %v", err, compose.ErrMissingSlot)
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	if got, err := ParseColor("#004c84"); err != nil || got != (color.NRGBA{R: 0x00, G: 0x4c, B: 0x84, A: 255}) {
+		t.Errorf("got %v, %v", got, err)
+	}
+	if got, err := ParseColor("#fa0"); err != nil || got != (color.NRGBA{R: 0xff, G: 0xaa, B: 0x00, A: 255}) {
+		t.Errorf("got %v, %v", got, err)
+	}
+	for _, s := range []string{"004c84", "#00g", "#1234", ""} {
+		if _, err := ParseColor(s); !errors.Is(err, ErrBadColor) {
+			t.Errorf("%q: got %v expected %v", s, err, ErrBadColor)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"path/filepath"
+	"slices"
+
+	"tama/compose"
+	"tama/svgdoc"
+)
+
+type recolorOptions struct {
+	options
+	filter   compose.Filter
+	format   string
+	scale    float64
+	selected map[string]compose.Palette
+}
+
+// runRecolor writes every combination once per palette, the geometry is the
+// same and only the colors of the slots change
+func runRecolor(args []string, stdout, stderr io.Writer) int {
+	opts, err := parseRecolorFlags(args, stderr)
+	if err == flag.ErrHelp {
+		return 0
+	}
+	if err != nil {
+		return 2
+	}
+
+	bodies, bodyparts, ok := extractAll(opts.options, stdout, stderr)
+	if !ok {
+		return 1
+	}
+
+	// a palette missing a slot would draw holes, nothing is written then
+	used := compose.UsedSlots(bodies, bodyparts)
+	names := slices.Sorted(maps.Keys(opts.selected))
+	for _, name := range names {
+		if err := opts.selected[name].Check(used); err != nil {
+			fmt.Fprintf(stderr, "mixer: palette %s: %v\n", name, err)
+			ok = false
+		}
+	}
+	if !ok {
+		return 1
+	}
+	if opts.verbose {
+		fmt.Fprintf(stdout, "%d palettes, slots %v\n", len(names), used)
+	}
+
+	recoloredDir := filepath.Join(opts.outDir, "recolored")
+	for _, combination := range compose.Combinations(bodies, bodyparts, opts.filter) {
+		name := combination.Name()
+		composition, err := combination.Compose(opts.types)
+		if err != nil {
+			fmt.Fprintf(stderr, "mixer: recoloring %s: %v\n", name, err)
+			return 1
+		}
+		svgs, err := compose.Recolor(composition, opts.selected)
+		if err != nil {
+			fmt.Fprintf(stderr, "mixer: recoloring %s: %v\n", name, err)
+			return 1
+		}
+		for _, palette := range names {
+			dir := filepath.Join(recoloredDir, palette)
+			if opts.verbose || opts.dryRun {
+				fmt.Fprintln(stdout, filepath.Join(dir, name+"."+opts.format))
+			}
+			if opts.dryRun {
+				continue
+			}
+			if opts.format == "png" {
+				err = savePNG(dir, name, composition, opts.scale, opts.selected[palette])
+			} else {
+				err = svgdoc.Save(dir, name, svgs[palette])
+			}
+			if err != nil {
+				fmt.Fprintf(stderr, "mixer: writing %s: %v\n", name, err)
+				return 1
+			}
+		}
+	}
+	return 0
+}
+
+func parseRecolorFlags(args []string, stderr io.Writer) (recolorOptions, error) {
+	var opts recolorOptions
+	var palettes string
+	fs := flag.NewFlagSet("mixer recolor", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: mixer recolor [flags] [sheet.svg ...]\n\n")
+		fmt.Fprintf(stderr, "Writes every combination with each palette of the manifest given by -palettes (default sheet %s).\n\n", defaultInput)
+		fs.PrintDefaults()
+	}
+	fs.StringVar(&opts.outDir, "out", "out", "output root, combinations are written in recolored/<palette>")
+	addExtractFlags(fs, &opts.options, "list the files that would be written without writing them")
+	filter := addFilterFlags(fs, "recolor")
+	fs.StringVar(&palettes, "palette", "", "comma separated palette names, all the palettes of the manifest by default")
+	fs.StringVar(&opts.format, "format", "svg", "output format, svg or png")
+	fs.Float64Var(&opts.scale, "scale", 8, "png pixels per sheet unit")
+	err := fs.Parse(args)
+	if err != nil {
+		return opts, err
+	}
+	if opts.format != "svg" && opts.format != "png" {
+		fmt.Fprintf(stderr, "mixer recolor: unknown format %q\n", opts.format)
+		return opts, errBadFlag
+	}
+	if opts.scale <= 0 {
+		fmt.Fprintf(stderr, "mixer recolor: scale must be positive\n")
+		return opts, errBadFlag
+	}
+	if err := useExtractFlags(fs, &opts.options, stderr); err != nil {
+		return opts, err
+	}
+	// without a manifest there is only the renderer palette
+	available := opts.palettes
+	if len(available) == 0 {
+		available = map[string]compose.Palette{"pet": compose.PetPalette}
+	}
+	opts.selected = available
+	if names := splitList(palettes); len(names) > 0 {
+		opts.selected = make(map[string]compose.Palette, len(names))
+		for _, name := range names {
+			palette, ok := available[name]
+			if !ok {
+				fmt.Fprintf(stderr, "mixer recolor: unknown palette %q\n", name)
+				return opts, errBadFlag
+			}
+			opts.selected[name] = palette
+		}
+	}
+	opts.filter = filter.filter()
+	return opts, nil
+}
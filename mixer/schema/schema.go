@@ -0,0 +1,179 @@
+// Package schema describes the JSON written by the mixer as JSON Schema, built
+// by reflection from the exported Go types, and checks documents against it.
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+)
+
+// Draft is the JSON Schema dialect of the documents
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is the subset of JSON Schema the mixer writes and validates.
+// AdditionalProperties is either false or a *Schema.
+type Schema struct {
+	Draft                string             `json:"$schema,omitempty"`
+	ID                   string             `json:"$id,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Const                any                `json:"const,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties any                `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+}
+
+var ErrNameTaken = errors.New("definition name already taken")
+
+// Root is a type defined under its own name in a document
+type Root struct {
+	Name string
+	Type reflect.Type
+}
+
+// Document is a schema defining every root type under its name. Structs they
+// are made of are defined under their Go type name, prefixed with their
+// package name when another package took it first.
+func Document(id string, roots ...Root) (*Schema, error) {
+	g := generator{defs: make(map[string]*Schema), names: make(map[reflect.Type]string)}
+	for _, root := range roots {
+		if other, ok := g.taken(root.Name); ok {
+			return nil, fmt.Errorf("%w: %s by %s", ErrNameTaken, root.Name, other)
+		}
+		g.names[root.Type] = root.Name
+	}
+	for _, root := range roots {
+		if err := g.define(root.Type); err != nil {
+			return nil, err
+		}
+	}
+	return &Schema{Draft: Draft, ID: id, Defs: g.defs}, nil
+}
+
+// Def returns the reference to a definition of the document
+func Def(name string) *Schema {
+	return &Schema{Ref: "#/$defs/" + name}
+}
+
+type generator struct {
+	defs  map[string]*Schema
+	names map[reflect.Type]string
+}
+
+// define adds the struct t to the definitions, along with the structs of its
+// fields
+func (g *generator) define(t reflect.Type) error {
+	name, ok := g.names[t]
+	if !ok {
+		name = t.Name()
+		if _, ok := g.taken(name); ok {
+			pkg := path.Base(t.PkgPath())
+			name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+		}
+		if other, ok := g.taken(name); ok {
+			return fmt.Errorf("%w: %s by %s", ErrNameTaken, name, other)
+		}
+		g.names[t] = name
+	}
+	if _, ok := g.defs[name]; ok {
+		return nil
+	}
+	def := &Schema{Type: "object", Properties: make(map[string]*Schema), Required: make([]string, 0), AdditionalProperties: false}
+	g.defs[name] = def
+	for _, field := range Fields(t) {
+		s, err := g.reflect(field.Type)
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", t.Name(), field.GoName, err)
+		}
+		def.Properties[field.Name] = s
+		if !field.Optional {
+			def.Required = append(def.Required, field.Name)
+		}
+	}
+	return nil
+}
+
+// taken returns the type defined under name
+func (g *generator) taken(name string) (reflect.Type, bool) {
+	for t, taken := range g.names {
+		if taken == name {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+func (g *generator) reflect(t reflect.Type) (*Schema, error) {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.reflect(t.Elem())
+	case reflect.String:
+		return &Schema{Type: "string"}, nil
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}, nil
+	case reflect.Interface:
+		return &Schema{}, nil
+	case reflect.Slice, reflect.Array:
+		items, err := g.reflect(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "array", Items: items}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("map key %s is not a string", t.Key())
+		}
+		values, err := g.reflect(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "object", AdditionalProperties: values}, nil
+	case reflect.Struct:
+		if err := g.define(t); err != nil {
+			return nil, err
+		}
+		return Def(g.names[t]), nil
+	}
+	return nil, fmt.Errorf("no schema for %s", t)
+}
+
+// Field is an exported struct field as encoding/json writes it
+type Field struct {
+	GoName   string
+	Name     string
+	Type     reflect.Type
+	Optional bool
+}
+
+// Fields lists the fields of a struct encoding/json writes, in their
+// declaration order. Optional fields are the omitempty ones.
+func Fields(t reflect.Type) []Field {
+	fields := make([]Field, 0, t.NumField())
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = f.Name
+		}
+		fields = append(fields, Field{GoName: f.Name, Name: name, Type: f.Type, Optional: strings.Contains(","+options+",", ",omitempty,")})
+	}
+	return fields
+}
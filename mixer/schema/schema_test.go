@@ -0,0 +1,117 @@
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+type testPoint struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+type testShape struct {
+	Name    string               `json:"name"`
+	Points  []testPoint          `json:"points"`
+	Closed  bool                 `json:"closed,omitempty"`
+	Tags    map[string]int       `json:"tags,omitempty"`
+	Origin  *testPoint           `json:"origin,omitempty"`
+	Skipped string               `json:"-"`
+	Named   map[string]testPoint `json:"named,omitempty"`
+}
+
+func testDocument(t *testing.T) *Schema {
+	t.Helper()
+	doc, err := Document("", Root{Name: "shape", Type: reflect.TypeFor[testShape]()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func TestDocument(t *testing.T) {
+	doc := testDocument(t)
+	if len(doc.Defs) != 2 || doc.Defs["shape"] == nil || doc.Defs["testPoint"] == nil {
+		t.Fatalf("got definitions %v expected shape and testPoint", doc.Defs)
+	}
+	shape := doc.Defs["shape"]
+	if !slices.Equal(shape.Required, []string{"name", "points"}) {
+		t.Errorf("got required %v", shape.Required)
+	}
+	if _, ok := shape.Properties["Skipped"]; ok {
+		t.Errorf("ignored fields are left out")
+	}
+	if points := shape.Properties["points"]; points.Type != "array" || points.Items.Ref != "#/$defs/testPoint" {
+		t.Errorf("got points %+v", points)
+	}
+	if tags := shape.Properties["tags"]; tags.Type != "object" || tags.AdditionalProperties.(*Schema).Type != "integer" {
+		t.Errorf("got tags %+v", tags)
+	}
+
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded["$schema"] != Draft || decoded["$defs"].(map[string]any)["shape"].(map[string]any)["additionalProperties"] != false {
+		t.Errorf("got %s", data)
+	}
+}
+
+func TestDocumentNameTaken(t *testing.T) {
+	_, err := Document("",
+		Root{Name: "shape", Type: reflect.TypeFor[testShape]()},
+		Root{Name: "shape", Type: reflect.TypeFor[testPoint]()},
+	)
+	if !errors.Is(err, ErrNameTaken) {
+		t.Errorf("got %v expected %v", err, ErrNameTaken)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	doc := testDocument(t)
+	doc.Defs["shape"].Properties["closed"] = &Schema{Type: "boolean", Const: true}
+
+	tests := []struct {
+		name string
+		json string
+		want []error
+	}{
+		{"valid", `{"name": "square", "points": [{"x": 0, "y": 1.5}], "tags": {"size": 2}}`, nil},
+		{"wrong type", `{"name": 3, "points": []}`, []error{ErrType}},
+		{"integer", `{"name": "a", "points": [], "tags": {"size": 2.5}}`, []error{ErrType}},
+		{"missing", `{"points": [{"x": 0}]}`, []error{ErrRequired, ErrRequired}},
+		{"unknown", `{"name": "a", "points": [], "color": "red"}`, []error{ErrUnknownProperty}},
+		{"const", `{"name": "a", "points": [], "closed": false}`, []error{ErrConst}},
+		{"not an object", `[]`, []error{ErrType}},
+	}
+	for _, tc := range tests {
+		var v any
+		if err := json.Unmarshal([]byte(tc.json), &v); err != nil {
+			t.Fatal(err)
+		}
+		errs := doc.Validate("shape", v)
+		if len(errs) != len(tc.want) {
+			t.Errorf("%s: got %v expected %v", tc.name, errs, tc.want)
+			continue
+		}
+		for i, err := range errs {
+			if !errors.Is(err, tc.want[i]) {
+				t.Errorf("%s: got %v expected %v", tc.name, err, tc.want[i])
+			}
+		}
+	}
+
+	var v any
+	_ = json.Unmarshal([]byte(`{"name": "a", "points": [{"x": 0, "y": "1"}]}`), &v)
+	var e *Error
+	if errs := doc.Validate("shape", v); len(errs) != 1 || !errors.As(errs[0], &e) || e.Path != "/points/0/y" {
+		t.Errorf("got %v expected an error at /points/0/y", errs)
+	}
+}
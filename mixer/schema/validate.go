@@ -0,0 +1,125 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrType            = errors.New("wrong type")
+	ErrRequired        = errors.New("missing property")
+	ErrUnknownProperty = errors.New("unknown property")
+	ErrConst           = errors.New("unexpected value")
+	ErrBadRef          = errors.New("unknown definition")
+)
+
+// Error is a value not matching the schema, Path is its JSON pointer
+type Error struct {
+	Path string
+	Err  error
+}
+
+func (e *Error) Error() string {
+	if e.Path == "" {
+		return e.Err.Error()
+	}
+	return e.Path + ": " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Validate checks a value decoded by encoding/json against the definition
+// name of the document, every mismatch is returned
+func (doc *Schema) Validate(name string, v any) []error {
+	return doc.validate(Def(name), v, "")
+}
+
+func (doc *Schema) validate(s *Schema, v any, path string) []error {
+	if s.Ref != "" {
+		def, ok := doc.Defs[strings.TrimPrefix(s.Ref, "#/$defs/")]
+		if !ok {
+			return []error{&Error{Path: path, Err: fmt.Errorf("%w %s", ErrBadRef, s.Ref)}}
+		}
+		return doc.validate(def, v, path)
+	}
+	if s.Type != "" && !hasType(v, s.Type) {
+		return []error{&Error{Path: path, Err: fmt.Errorf("%w: %s expected", ErrType, s.Type)}}
+	}
+	if s.Const != nil && !sameValue(s.Const, v) {
+		return []error{&Error{Path: path, Err: fmt.Errorf("%w %v, %v expected", ErrConst, v, s.Const)}}
+	}
+
+	errs := make([]error, 0)
+	switch v := v.(type) {
+	case []any:
+		if s.Items == nil {
+			break
+		}
+		for i, item := range v {
+			errs = append(errs, doc.validate(s.Items, item, path+"/"+strconv.Itoa(i))...)
+		}
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				errs = append(errs, &Error{Path: path, Err: fmt.Errorf("%w %s", ErrRequired, name)})
+			}
+		}
+		for _, name := range slices.Sorted(maps.Keys(v)) {
+			child := path + "/" + escape(name)
+			if property, ok := s.Properties[name]; ok {
+				errs = append(errs, doc.validate(property, v[name], child)...)
+				continue
+			}
+			switch additional := s.AdditionalProperties.(type) {
+			case bool:
+				if !additional {
+					errs = append(errs, &Error{Path: path, Err: fmt.Errorf("%w %s", ErrUnknownProperty, name)})
+				}
+			case *Schema:
+				errs = append(errs, doc.validate(additional, v[name], child)...)
+			}
+		}
+	}
+	return errs
+}
+
+func hasType(v any, t string) bool {
+	switch v := v.(type) {
+	case nil:
+		return t == "null"
+	case bool:
+		return t == "boolean"
+	case string:
+		return t == "string"
+	case float64:
+		return t == "number" || t == "integer" && v == math.Trunc(v)
+	case []any:
+		return t == "array"
+	case map[string]any:
+		return t == "object"
+	}
+	return false
+}
+
+// sameValue compares a constant of the schema with a decoded value, numbers
+// are decoded as float64
+func sameValue(c, v any) bool {
+	switch c := c.(type) {
+	case int:
+		return v == float64(c)
+	}
+	return reflect.DeepEqual(c, v)
+}
+
+// escape makes a property name a JSON pointer token
+func escape(name string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(name, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,177 @@
+// Package sprite renders bodies and bodyparts into a packed sprite sheet, with
+// an atlas describing where each frame is, for engines without vector drawing.
+package sprite
+
+import (
+	"image"
+	"image/draw"
+	"math"
+	"slices"
+	"strconv"
+
+	"tama/compose"
+	"tama/geom"
+	"tama/pet"
+	"tama/raster"
+)
+
+// padding is the number of empty pixels kept around every frame of the sheet
+const padding = 1
+
+// Frame is a sprite of the sheet. Origin is the position, in pixels from the
+// top left of the frame, of the sheet origin: the top left of a body bounding
+// box, or the root anchor of a bodypart. Anchors are the body anchors in the
+// same pixels, T stays in degrees.
+type Frame struct {
+	Name    string      `json:"name"`
+	X       int         `json:"x"`
+	Y       int         `json:"y"`
+	Width   int         `json:"width"`
+	Height  int         `json:"height"`
+	Origin  geom.Point  `json:"origin"`
+	Anchors []pet.Point `json:"anchors,omitempty"`
+}
+
+// Animation lists the frames of a body or a bodypart, ordered by frame
+type Animation struct {
+	Name   string   `json:"name"`
+	Frames []string `json:"frames"`
+}
+
+type Atlas struct {
+	Version    int         `json:"version"`
+	Image      string      `json:"image"`
+	Width      int         `json:"width"`
+	Height     int         `json:"height"`
+	Scale      float64     `json:"scale"`
+	Frames     []Frame     `json:"frames"`
+	Animations []Animation `json:"animations"`
+}
+
+// sprite is a frame waiting to be packed
+type sprite struct {
+	frame Frame
+	image *image.NRGBA
+}
+
+// Build renders every body and bodypart frame at scale and packs them into a
+// single image. Bodies are named after their name, bodyparts after their type
+// and name ("arm1-stick"), frames add their number ("arm1-stick-0").
+func Build(bodies []pet.Body, bodyparts []pet.BodyPart, types pet.Registry, scale float64, palette compose.Palette) (*image.NRGBA, Atlas, error) {
+	atlas := Atlas{Scale: scale, Frames: make([]Frame, 0), Animations: make([]Animation, 0)}
+	sprites := make([]sprite, 0)
+
+	for _, group := range pet.GroupBodies(slices.Clone(bodies)) {
+		animation := Animation{Name: group[0].Name}
+		for _, body := range group {
+			c := compose.Composition{Body: compose.BodyPaths(body), BoundingBox: body.BoundingBox}
+			s, err := render(animation.Name+"-"+strconv.Itoa(body.Frame), c, scale, palette)
+			if err != nil {
+				return nil, Atlas{}, err
+			}
+			for _, anchor := range body.Points {
+				p := s.toPixels(anchor.Position(), scale)
+				anchor.X, anchor.Y = p.X, p.Y
+				s.frame.Anchors = append(s.frame.Anchors, anchor)
+			}
+			sprites = append(sprites, s)
+			animation.Frames = append(animation.Frames, s.frame.Name)
+		}
+		atlas.Animations = append(atlas.Animations, animation)
+	}
+
+	for _, group := range pet.GroupBodyParts(slices.Clone(bodyparts)) {
+		animation := Animation{Name: string(group[0].Type) + "-" + group[0].Name}
+		for _, part := range group {
+			c := compose.Composition{BoundingBox: part.BoundingBox}
+			if types.Inside(part.Type) {
+				c.In = compose.PartPaths(part)
+			} else {
+				c.Out = compose.PartPaths(part)
+			}
+			s, err := render(animation.Name+"-"+strconv.Itoa(part.Frame), c, scale, palette)
+			if err != nil {
+				return nil, Atlas{}, err
+			}
+			sprites = append(sprites, s)
+			animation.Frames = append(animation.Frames, s.frame.Name)
+		}
+		atlas.Animations = append(atlas.Animations, animation)
+	}
+
+	sheet := pack(sprites)
+	atlas.Width, atlas.Height = sheet.Bounds().Dx(), sheet.Bounds().Dy()
+	for _, s := range sprites {
+		atlas.Frames = append(atlas.Frames, s.frame)
+	}
+	return sheet, atlas, nil
+}
+
+func render(name string, c compose.Composition, scale float64, palette compose.Palette) (sprite, error) {
+	img, err := raster.Rasterize(c, scale, palette)
+	if err != nil {
+		return sprite{}, &FrameError{Name: name, Err: err}
+	}
+	s := sprite{image: img, frame: Frame{Name: name, Width: img.Bounds().Dx(), Height: img.Bounds().Dy()}}
+	s.frame.Origin = raster.Origin(c, scale)
+	return s, nil
+}
+
+// toPixels converts a point of the sheet to pixels from the top left of the
+// frame
+func (s sprite) toPixels(p geom.Point, scale float64) geom.Point {
+	return geom.Point{X: s.frame.Origin.X + p.X*scale, Y: s.frame.Origin.Y + p.Y*scale}
+}
+
+// pack places the sprites in rows, tallest first, in a sheet about as wide as
+// high, and sets the position of their frames
+func pack(sprites []sprite) *image.NRGBA {
+	area := 0
+	width := 0
+	for _, s := range sprites {
+		area += (s.frame.Width + padding) * (s.frame.Height + padding)
+		width = max(width, s.frame.Width+2*padding)
+	}
+	width = max(width, int(math.Ceil(math.Sqrt(float64(area)))))
+
+	order := make([]int, len(sprites))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return sprites[b].frame.Height - sprites[a].frame.Height
+	})
+
+	x, y, rowHeight := padding, padding, 0
+	for _, i := range order {
+		f := &sprites[i].frame
+		if x+f.Width+padding > width {
+			x, y = padding, y+rowHeight+padding
+			rowHeight = 0
+		}
+		f.X, f.Y = x, y
+		x += f.Width + padding
+		rowHeight = max(rowHeight, f.Height)
+	}
+
+	sheet := image.NewNRGBA(image.Rect(0, 0, width, y+rowHeight+padding))
+	for _, s := range sprites {
+		r := image.Rect(s.frame.X, s.frame.Y, s.frame.X+s.frame.Width, s.frame.Y+s.frame.Height)
+		draw.Draw(sheet, r, s.image, image.Point{}, draw.Src)
+	}
+	return sheet
+}
+
+// FrameError reports a frame that could not be rendered
+type FrameError struct {
+	Name string
+	Err  error
+}
+
+func (e *FrameError) Error() string {
+	return "frame " + strconv.Quote(e.Name) + ": " + e.Err.Error()
+}
+
+func (e *FrameError) Unwrap() error {
+	return e.Err
+}
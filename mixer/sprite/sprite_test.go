@@ -0,0 +1,70 @@
+package sprite
+
+import (
+	"image"
+	"testing"
+
+	"tama/compose"
+	"tama/geom"
+	"tama/pet"
+)
+
+func TestBuild(t *testing.T) {
+	square := geom.Rect{TopLeft: geom.Point{X: 0, Y: 0}, BottomRight: geom.Point{X: 10, Y: 10}}
+	bodies := []pet.Body{
+		{Name: "box", Frame: 1, Path: "M 0 0 L 10 0 L 10 10 L 0 10 Z", BoundingBox: square},
+		{Name: "box", Frame: 0, Path: "M 0 0 L 10 0 L 10 10 L 0 10 Z", BoundingBox: square,
+			Points: []pet.Point{{X: 5, Y: 10, T: 180, Type: pet.BodypartType_Leg1}}},
+	}
+	bodyparts := []pet.BodyPart{
+		{Name: "stick", Type: pet.BodypartType_Leg1, Frame: 1, Path: "M -1 0 L 1 0 L 1 -4 L -1 -4 Z",
+			BoundingBox: geom.Rect{TopLeft: geom.Point{X: -1, Y: -4}, BottomRight: geom.Point{X: 1, Y: 0}}},
+		{Name: "stick", Type: pet.BodypartType_Leg1, Frame: 0, Path: "M -1 0 L 1 0 L 1 -3 L -1 -3 Z",
+			BoundingBox: geom.Rect{TopLeft: geom.Point{X: -1, Y: -3}, BottomRight: geom.Point{X: 1, Y: 0}}},
+	}
+	sheet, atlas, err := Build(bodies, bodyparts, pet.DefaultRegistry, 2, compose.PetPalette)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Animation{
+		{Name: "box", Frames: []string{"box-0", "box-1"}},
+		{Name: "leg1-stick", Frames: []string{"leg1-stick-0", "leg1-stick-1"}},
+	}
+	if len(atlas.Animations) != len(want) {
+		t.Fatalf("got animations %v expected %v", atlas.Animations, want)
+	}
+	for i, w := range want {
+		got := atlas.Animations[i]
+		if got.Name != w.Name || len(got.Frames) != 2 || got.Frames[0] != w.Frames[0] || got.Frames[1] != w.Frames[1] {
+			t.Errorf("got animation %v expected %v", got, w)
+		}
+	}
+
+	frames := map[string]Frame{}
+	for i, f := range atlas.Frames {
+		frames[f.Name] = f
+		r := image.Rect(f.X, f.Y, f.X+f.Width, f.Y+f.Height)
+		if !r.In(sheet.Bounds()) {
+			t.Errorf("%s is out of the sheet", f.Name)
+		}
+		for _, other := range atlas.Frames[i+1:] {
+			if r.Overlaps(image.Rect(other.X, other.Y, other.X+other.Width, other.Y+other.Height)) {
+				t.Errorf("%s overlaps %s", f.Name, other.Name)
+			}
+		}
+	}
+
+	// the outline adds half a unit around the box
+	box := frames["box-0"]
+	if box.Width != 22 || box.Height != 22 || box.Origin != (geom.Point{X: 1, Y: 1}) {
+		t.Errorf("got body frame %+v", box)
+	}
+	if len(box.Anchors) != 1 || box.Anchors[0].X != 11 || box.Anchors[0].Y != 21 || box.Anchors[0].T != 180 {
+		t.Errorf("got anchors %v expected leg1 at (11, 21)", box.Anchors)
+	}
+	// the bodypart origin is its root anchor
+	if stick := frames["leg1-stick-1"]; stick.Origin != (geom.Point{X: 3, Y: 9}) {
+		t.Errorf("got bodypart origin %v expected (3, 9)", stick.Origin)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"tama/compose"
+	"tama/export"
+	"tama/sprite"
+)
+
+type spritesOptions struct {
+	options
+	name    string
+	scale   float64
+	palette compose.Palette
+}
+
+// runSprites renders every body and bodypart frame into a sprite sheet and
+// its JSON atlas
+func runSprites(args []string, stdout, stderr io.Writer) int {
+	opts, err := parseSpritesFlags(args, stderr)
+	if err == flag.ErrHelp {
+		return 0
+	}
+	if err != nil {
+		return 2
+	}
+
+	bodies, bodyparts, ok := extractAll(opts.options, stdout, stderr)
+	if !ok {
+		return 1
+	}
+
+	sheet, atlas, err := sprite.Build(bodies, bodyparts, opts.types, opts.scale, opts.palette)
+	if err != nil {
+		fmt.Fprintf(stderr, "mixer: %v\n", err)
+		return 1
+	}
+	if opts.verbose || opts.dryRun {
+		fmt.Fprintf(stdout, "%s (%dx%d, %d frames)\n", filepath.Join(opts.outDir, opts.name+".png"), atlas.Width, atlas.Height, len(atlas.Frames))
+		fmt.Fprintf(stdout, "%s (%d animations)\n", filepath.Join(opts.outDir, opts.name+".json"), len(atlas.Animations))
+	}
+	if opts.dryRun {
+		return 0
+	}
+	if err := export.SaveSpriteSheet(opts.outDir, opts.name, sheet, atlas); err != nil {
+		fmt.Fprintf(stderr, "mixer: writing sprite sheet: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+func parseSpritesFlags(args []string, stderr io.Writer) (spritesOptions, error) {
+	var opts spritesOptions
+	var palette string
+	fs := flag.NewFlagSet("mixer sprites", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: mixer sprites [flags] [sheet.svg ...]\n\n")
+		fmt.Fprintf(stderr, "Renders every body and bodypart frame into a sprite sheet PNG and a JSON atlas (default sheet %s).\n\n", defaultInput)
+		fs.PrintDefaults()
+	}
+	fs.StringVar(&opts.outDir, "out", "out", "output directory")
+	fs.StringVar(&opts.name, "name", "sprites", "file name of the sheet and the atlas, without extension")
+	addExtractFlags(fs, &opts.options, "render and list the files that would be written without writing them")
+	fs.Float64Var(&opts.scale, "scale", 8, "pixels per sheet unit")
+	fs.StringVar(&palette, "palette", "", paletteUsage)
+	err := fs.Parse(args)
+	if err != nil {
+		return opts, err
+	}
+	if opts.scale <= 0 {
+		fmt.Fprintf(stderr, "mixer sprites: scale must be positive\n")
+		return opts, errBadFlag
+	}
+	if err := useExtractFlags(fs, &opts.options, stderr); err != nil {
+		return opts, err
+	}
+	if opts.palette, err = parsePalette(palette, opts.palettes); err != nil {
+		fmt.Fprintf(stderr, "mixer sprites: palette: %v\n", err)
+		return opts, errBadFlag
+	}
+	return opts, nil
+}
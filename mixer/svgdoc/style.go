@@ -0,0 +1,87 @@
+package svgdoc
+
+import (
+	"strconv"
+	"strings"
+)
+
+// styleProperty returns the value of a property of a style attribute
+// ("fill:none;stroke:#000000"), false when it is not set
+func styleProperty(style string, name string) (string, bool) {
+	for _, declaration := range strings.Split(style, ";") {
+		key, value, ok := strings.Cut(declaration, ":")
+		if ok && strings.TrimSpace(key) == name {
+			return strings.TrimSpace(value), true
+		}
+	}
+	return "", false
+}
+
+// StrokeWidth returns the width of the outline drawn by the style of the
+// path, 0 when it has no stroke
+func (path *Path) StrokeWidth() float64 {
+	stroke, ok := styleProperty(path.Style, "stroke")
+	if !ok || stroke == "none" {
+		return 0
+	}
+	value, ok := styleProperty(path.Style, "stroke-width")
+	if !ok {
+		// SVG default
+		return 1
+	}
+	width, err := strconv.ParseFloat(strings.TrimSuffix(value, "px"), 64)
+	if err != nil || width < 0 {
+		return 1
+	}
+	return width
+}
+
+// Style holds the properties of a path style the renderer can reproduce.
+// Colors are lower case "#rrggbb" when they could be read, or "none".
+type Style struct {
+	Fill        string
+	Stroke      string
+	StrokeWidth float64
+	Opacity     float64
+	FillRule    string
+}
+
+// namedColors are the color keywords found in hand edited sheets
+var namedColors = map[string]string{
+	"black": "#000000",
+	"white": "#ffffff",
+}
+
+// normalizeColor writes a color as "#rrggbb", unknown colors are left as they
+// are but lower cased
+func normalizeColor(color string) string {
+	color = strings.ToLower(strings.TrimSpace(color))
+	if hex, ok := namedColors[color]; ok {
+		return hex
+	}
+	if len(color) == 4 && color[0] == '#' {
+		return string([]byte{'#', color[1], color[1], color[2], color[2], color[3], color[3]})
+	}
+	return color
+}
+
+// ParseStyle reads the style attribute of the path, missing properties take
+// their SVG default: black fill, no stroke, full opacity, nonzero fill rule
+func (path *Path) ParseStyle() Style {
+	style := Style{Fill: "#000000", Stroke: "none", StrokeWidth: path.StrokeWidth(), Opacity: 1, FillRule: "nonzero"}
+	if fill, ok := styleProperty(path.Style, "fill"); ok {
+		style.Fill = normalizeColor(fill)
+	}
+	if stroke, ok := styleProperty(path.Style, "stroke"); ok {
+		style.Stroke = normalizeColor(stroke)
+	}
+	if value, ok := styleProperty(path.Style, "opacity"); ok {
+		if opacity, err := strconv.ParseFloat(value, 64); err == nil {
+			style.Opacity = min(max(opacity, 0), 1)
+		}
+	}
+	if rule, ok := styleProperty(path.Style, "fill-rule"); ok && (rule == "nonzero" || rule == "evenodd") {
+		style.FillRule = rule
+	}
+	return style
+}
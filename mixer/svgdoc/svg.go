@@ -0,0 +1,263 @@
+// Package svgdoc models the subset of an SVG document the mixer reads from
+// art sheets: layers (groups), paths, ellipses and circles.
+package svgdoc
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"tama/geom"
+)
+
+// ElementError reports a problem with a single element of a document
+type ElementError struct {
+	ID  string
+	Err error
+}
+
+func (e *ElementError) Error() string {
+	return "element " + strconv.Quote(e.ID) + ": " + e.Err.Error()
+}
+
+func (e *ElementError) Unwrap() error {
+	return e.Err
+}
+
+type SVG struct {
+	XMLName xml.Name `xml:"svg"`
+	Width   string   `xml:"width,attr"`
+	Height  string   `xml:"height,attr"`
+	ViewBox string   `xml:"viewBox,attr"`
+	Xmlns   string   `xml:"xmlns,attr"`
+
+	Groups []Group `xml:"g"`
+}
+
+func (s SVG) String() string {
+	data, err := xml.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return "" // ou gérer l'erreur selon votre besoin
+	}
+	return string(data)
+}
+
+type Group struct {
+	ID            string `xml:"id,attr"`
+	Label         string `xml:"label,attr,omitempty"`
+	TransformList string `xml:"transform,attr,omitempty"`
+
+	Groups   []Group   `xml:"g"`
+	Paths    []Path    `xml:"path"`
+	Ellipses []Ellipse `xml:"ellipse"`
+	Circles  []Circle  `xml:"circle"`
+}
+
+func GroupCopy(group Group) Group {
+	g := group
+	groups := make([]Group, 0)
+	for _, gg := range group.Groups {
+		groups = append(groups, GroupCopy(gg))
+	}
+	g.Groups = groups
+	paths := make([]Path, len(group.Paths))
+	copy(paths, group.Paths)
+	g.Paths = paths
+	ellipses := make([]Ellipse, len(group.Ellipses))
+	copy(ellipses, group.Ellipses)
+	g.Ellipses = ellipses
+	circles := make([]Circle, len(group.Circles))
+	copy(circles, group.Circles)
+	g.Circles = circles
+	return g
+}
+
+func (group Group) GetPath() Path {
+	paths := GetPathsInGroup(group)
+	resultCmds := make([]string, 0)
+	for _, path := range paths {
+		resultCmds = append(resultCmds, path.D)
+	}
+	return Path{
+		D: strings.Join(resultCmds, " "),
+	}
+}
+
+// GetBoundingBox returns the exact box around every path of the group and its
+// sub-groups, errors name the faulty path
+func (group Group) GetBoundingBox() (geom.Rect, error) {
+	var result geom.Rect
+	paths := GetPathsInGroup(group)
+	if len(paths) == 0 {
+		return geom.Rect{}, &ElementError{ID: group.ID, Err: ErrEmptyPath}
+	}
+	for i, path := range paths {
+		rect, err := path.GetBoundingBox()
+		if err != nil {
+			return geom.Rect{}, err
+		}
+		if i == 0 {
+			result = rect
+		} else {
+			result = result.Union(rect)
+		}
+	}
+	return result, nil
+}
+
+// Apply transformations to a group, for then, all coords are absolute.
+// The tree is kept as is: every sub-group is transformed along with its own
+// paths, ellipses and circles.
+func (group Group) Transform(t geom.Matrix) (Group, error) {
+	result := GroupCopy(group)
+
+	for i, el := range result.Ellipses {
+		p := t.Apply(geom.Point{X: el.CX, Y: el.CY})
+		result.Ellipses[i].CX = p.X
+		result.Ellipses[i].CY = p.Y
+	}
+
+	for i, ci := range result.Circles {
+		p := t.Apply(geom.Point{X: ci.CX, Y: ci.CY})
+		result.Circles[i].CX = p.X
+		result.Circles[i].CY = p.Y
+	}
+
+	for i, path := range result.Paths {
+		commands, err := geom.ParseD(path.D)
+		if err != nil {
+			return Group{}, &ElementError{ID: path.ID, Err: err}
+		}
+		result.Paths[i].D = geom.CompileD(t.TransformCommands(commands))
+	}
+
+	for i, g := range group.Groups {
+		transformed, err := g.Transform(t)
+		if err != nil {
+			return Group{}, err
+		}
+		result.Groups[i] = transformed
+	}
+	return result, nil
+}
+
+type Ellipse struct {
+	ID            string  `xml:"id,attr"`
+	Label         string  `xml:"label,attr,omitempty"`
+	CX            float64 `xml:"cx,attr"`
+	CY            float64 `xml:"cy,attr"`
+	RX            float64 `xml:"rx,attr"`
+	RY            float64 `xml:"ry,attr"`
+	TransformList string  `xml:"transform,attr,omitempty"`
+}
+
+type Circle struct {
+	ID            string  `xml:"id,attr"`
+	Label         string  `xml:"label,attr,omitempty"`
+	CX            float64 `xml:"cx,attr"`
+	CY            float64 `xml:"cy,attr"`
+	R             float64 `xml:"r,attr"`
+	TransformList string  `xml:"transform,attr,omitempty"`
+}
+
+type Path struct {
+	ID            string `xml:"id,attr"`
+	Label         string `xml:"label,attr,omitempty"`
+	D             string `xml:"d,attr"`
+	Style         string `xml:"style,attr,omitempty"`
+	TransformList string `xml:"transform,attr,omitempty"`
+}
+
+var ErrEmptyPath = errors.New("path has no segment")
+
+// GetBoundingBox returns the exact bounding box of the path geometry, stroke
+// left aside. A path without any segment returns ErrEmptyPath.
+func (path *Path) GetBoundingBox() (geom.Rect, error) {
+	cmds, err := geom.ParseD(path.D)
+	if err != nil {
+		return geom.Rect{}, &ElementError{ID: path.ID, Err: err}
+	}
+	rect, ok := geom.BeziersBounds(geom.GetBeziersFromCommands(cmds))
+	if !ok {
+		return geom.Rect{}, &ElementError{ID: path.ID, Err: ErrEmptyPath}
+	}
+	return rect, nil
+}
+
+// GetStrokeBoundingBox is GetBoundingBox grown by half the stroke width found
+// in the style of the path
+func (path *Path) GetStrokeBoundingBox() (geom.Rect, error) {
+	rect, err := path.GetBoundingBox()
+	if err != nil {
+		return geom.Rect{}, err
+	}
+	return rect.Inset(-path.StrokeWidth() / 2), nil
+}
+
+// Save writes the document to basepath/name.svg, creating basepath if needed
+func Save(basepath, name string, svg SVG) error {
+	if err := os.MkdirAll(basepath, 0755); err != nil {
+		return err
+	}
+	out, err := xml.MarshalIndent(svg, "", "  ")
+	if err != nil {
+		return err
+	}
+	out = append([]byte(xml.Header), append(out, '\n')...)
+	return os.WriteFile(filepath.Join(basepath, name+".svg"), out, 0644)
+}
+
+func GetPathsInGroup(group Group) []Path {
+	paths := group.Paths
+	for _, g := range group.Groups {
+		paths = append(paths, GetPathsInGroup(g)...)
+	}
+	return paths
+}
+
+func GetPathsInSVG(svg SVG) []Path {
+	paths := make([]Path, 0)
+	for _, g := range svg.Groups {
+		paths = append(paths, GetPathsInGroup(g)...)
+	}
+	return paths
+}
+
+func CleanGroup(group *Group) {
+	for i := 0; i < len(group.Groups); i++ {
+		CleanGroup(&group.Groups[i])
+	}
+	group.Circles = slices.DeleteFunc(group.Circles, func(c Circle) bool { return c.Label == group.Label })
+	group.Ellipses = slices.DeleteFunc(group.Ellipses, func(c Ellipse) bool { return c.Label == group.Label })
+	group.Paths = slices.DeleteFunc(group.Paths, func(c Path) bool { return c.Label == group.Label })
+}
+
+// Decode reads a whole SVG document
+func Decode(r io.Reader) (SVG, error) {
+	var svg SVG
+	decoder := xml.NewDecoder(r)
+	if err := decoder.Decode(&svg); err != nil {
+		return SVG{}, err
+	}
+	return svg, nil
+}
+
+// Load reads the SVG document stored in filename
+func Load(filename string) (SVG, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return SVG{}, err
+	}
+	defer file.Close()
+	svg, err := Decode(file)
+	if err != nil {
+		return SVG{}, fmt.Errorf("%s: %w", filename, err)
+	}
+	return svg, nil
+}
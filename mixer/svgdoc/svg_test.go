@@ -0,0 +1,113 @@
+package svgdoc
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"tama/geom"
+)
+
+func TestGroupTransformNested(t *testing.T) {
+	group := Group{
+		ID:       "layer1",
+		Paths:    []Path{{ID: "body", Label: "body", D: "M 0 0 L 10 0", Style: "fill:#fff"}},
+		Ellipses: []Ellipse{{ID: "e1", Label: "eye", CX: 1, CY: 1}},
+		Groups: []Group{{
+			ID:       "eyes",
+			Paths:    []Path{{ID: "highlight", D: "M 1 1 L 2 2"}},
+			Ellipses: []Ellipse{{ID: "e2", Label: "eye", CX: 2, CY: 2}},
+			Groups: []Group{{
+				ID:      "deeper",
+				Circles: []Circle{{ID: "c1", Label: "mouth", CX: 3, CY: 3}},
+			}},
+		}},
+	}
+	got, err := group.Transform(geom.TranslateMatrix(10, 20))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Paths) != 1 || len(got.Groups) != 1 || len(got.Groups[0].Paths) != 1 || len(got.Groups[0].Groups) != 1 {
+		t.Fatal("the group tree must be kept")
+	}
+	if got.Paths[0].ID != "body" || got.Paths[0].Label != "body" || got.Paths[0].Style != "fill:#fff" {
+		t.Errorf("path attributes must be kept, got %+v", got.Paths[0])
+	}
+	if el := got.Groups[0].Ellipses[0]; el.CX != 12 || el.CY != 22 {
+		t.Errorf("nested ellipse is on (%f, %f) expected (12, 22)", el.CX, el.CY)
+	}
+	if ci := got.Groups[0].Groups[0].Circles[0]; ci.CX != 13 || ci.CY != 23 {
+		t.Errorf("nested circle is on (%f, %f) expected (13, 23)", ci.CX, ci.CY)
+	}
+	commands, err := geom.ParseD(got.Groups[0].Paths[0].D)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(commands[0].Args[0]-11) > 1e-6 || math.Abs(commands[0].Args[1]-21) > 1e-6 {
+		t.Errorf("nested path starts on (%f, %f) expected (11, 21)", commands[0].Args[0], commands[0].Args[1])
+	}
+	// the original group is left untouched
+	if group.Groups[0].Ellipses[0].CX != 2 || group.Groups[0].Groups[0].Circles[0].CX != 3 {
+		t.Error("Transform must not modify its receiver")
+	}
+}
+
+func TestPathGetBoundingBox(t *testing.T) {
+	path := Path{ID: "p", D: "M 130 10 C 120 20, 180 20, 170 10", Style: "fill:none;stroke:#000000;stroke-width:2"}
+	got, err := path.GetBoundingBox()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.TopLeft.Y != 10 || got.BottomRight.Y != 17.5 || got.TopLeft.X <= 120 || got.TopLeft.X >= 130 {
+		t.Errorf("got %v", got)
+	}
+	stroked, err := path.GetStrokeBoundingBox()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stroked.TopLeft.Y != 9 || stroked.BottomRight.Y != 18.5 || stroked.Width() != got.Width()+2 {
+		t.Errorf("got %v expected %v grown by 1", stroked, got)
+	}
+
+	empty := Path{ID: "empty", D: "M 10 10"}
+	if _, err := empty.GetBoundingBox(); !errors.Is(err, ErrEmptyPath) {
+		t.Errorf("expected ErrEmptyPath, got %v", err)
+	}
+}
+
+func TestPathStrokeWidth(t *testing.T) {
+	tests := []struct {
+		style string
+		want  float64
+	}{
+		{"", 0},
+		{"fill:#ff9631;stroke:none;stroke-width:0.235414", 0},
+		{"fill:none;stroke:#000000;stroke-width:0.724538", 0.724538},
+		{"stroke:#000000", 1},
+		{"stroke: #000000; stroke-width: 3px", 3},
+	}
+	for _, tc := range tests {
+		path := Path{Style: tc.style}
+		if got := path.StrokeWidth(); got != tc.want {
+			t.Errorf("%q: got %f expected %f", tc.style, got, tc.want)
+		}
+	}
+}
+
+func TestPathParseStyle(t *testing.T) {
+	tests := []struct {
+		style string
+		want  Style
+	}{
+		{"", Style{Fill: "#000000", Stroke: "none", StrokeWidth: 0, Opacity: 1, FillRule: "nonzero"}},
+		{"fill:none;stroke:#000000;stroke-width:0.264583", Style{Fill: "none", Stroke: "#000000", StrokeWidth: 0.264583, Opacity: 1, FillRule: "nonzero"}},
+		{"fill:#FFF;opacity:0.5;fill-rule:evenodd", Style{Fill: "#ffffff", Stroke: "none", Opacity: 0.5, FillRule: "evenodd"}},
+		{"fill:White;stroke:black;opacity:2;fill-rule:bad", Style{Fill: "#ffffff", Stroke: "#000000", StrokeWidth: 1, Opacity: 1, FillRule: "nonzero"}},
+	}
+	for _, tc := range tests {
+		path := Path{Style: tc.style}
+		if got := path.ParseStyle(); got != tc.want {
+			t.Errorf("%q: got %+v expected %+v", tc.style, got, tc.want)
+		}
+	}
+}
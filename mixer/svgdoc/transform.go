@@ -0,0 +1,77 @@
+package svgdoc
+
+import (
+	"math"
+
+	"tama/geom"
+)
+
+// elementMatrix composes parent with the transform attribute of an element
+func elementMatrix(parent geom.Matrix, id string, transform string) (geom.Matrix, error) {
+	m, err := geom.ParseTransform(transform)
+	if err != nil {
+		return geom.Matrix{}, &ElementError{ID: id, Err: err}
+	}
+	return parent.Multiply(m), nil
+}
+
+// ResolveTransforms bakes the transform attributes of group and of everything
+// it contains into their coordinates, parent being the transformation group is
+// already under (geom.Identity() for a top-level layer). The result has no
+// transform attribute left and only uses absolute coordinates.
+func (group Group) ResolveTransforms(parent geom.Matrix) (Group, error) {
+	m, err := elementMatrix(parent, group.ID, group.TransformList)
+	if err != nil {
+		return Group{}, err
+	}
+	result := GroupCopy(group)
+	result.TransformList = ""
+
+	for i, path := range result.Paths {
+		pm, err := elementMatrix(m, path.ID, path.TransformList)
+		if err != nil {
+			return Group{}, err
+		}
+		result.Paths[i].TransformList = ""
+		if pm.IsIdentity() {
+			continue
+		}
+		commands, err := geom.ParseD(path.D)
+		if err != nil {
+			return Group{}, &ElementError{ID: path.ID, Err: err}
+		}
+		result.Paths[i].D = geom.CompileD(pm.TransformCommands(commands))
+	}
+
+	for i, el := range result.Ellipses {
+		em, err := elementMatrix(m, el.ID, el.TransformList)
+		if err != nil {
+			return Group{}, err
+		}
+		center := em.Apply(geom.Point{X: el.CX, Y: el.CY})
+		result.Ellipses[i].CX, result.Ellipses[i].CY = center.X, center.Y
+		result.Ellipses[i].RX = el.RX * math.Hypot(em.A, em.B)
+		result.Ellipses[i].RY = el.RY * math.Hypot(em.C, em.D)
+		result.Ellipses[i].TransformList = ""
+	}
+
+	for i, ci := range result.Circles {
+		cm, err := elementMatrix(m, ci.ID, ci.TransformList)
+		if err != nil {
+			return Group{}, err
+		}
+		center := cm.Apply(geom.Point{X: ci.CX, Y: ci.CY})
+		result.Circles[i].CX, result.Circles[i].CY = center.X, center.Y
+		result.Circles[i].R = ci.R * math.Sqrt(math.Abs(cm.A*cm.D-cm.B*cm.C))
+		result.Circles[i].TransformList = ""
+	}
+
+	for i, g := range result.Groups {
+		resolved, err := g.ResolveTransforms(m)
+		if err != nil {
+			return Group{}, err
+		}
+		result.Groups[i] = resolved
+	}
+	return result, nil
+}
@@ -0,0 +1,61 @@
+package svgdoc
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"tama/geom"
+)
+
+func TestResolveTransformsNested(t *testing.T) {
+	layer := Group{
+		ID:            "layer1",
+		TransformList: "translate(10,0)",
+		Ellipses:      []Ellipse{{ID: "anchor", CX: 1, CY: 1, RX: 1, RY: 1, TransformList: "scale(-1,1)"}},
+		Groups: []Group{{
+			ID:            "g1",
+			TransformList: "scale(2)",
+			Paths:         []Path{{ID: "path1", D: "M 1 1 L 2 1"}},
+			Circles:       []Circle{{ID: "circle1", CX: 1, CY: 2, R: 1}},
+		}},
+	}
+	got, err := layer.ResolveTransforms(geom.Identity())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.TransformList != "" || got.Groups[0].TransformList != "" || got.Ellipses[0].TransformList != "" {
+		t.Error("transform attributes must be cleared once resolved")
+	}
+	if got.Ellipses[0].CX != 9 || got.Ellipses[0].CY != 1 {
+		t.Errorf("ellipse center is (%f, %f) expected (9, 1)", got.Ellipses[0].CX, got.Ellipses[0].CY)
+	}
+	circle := got.Groups[0].Circles[0]
+	if circle.CX != 12 || circle.CY != 4 || circle.R != 2 {
+		t.Errorf("circle is (%f, %f) r=%f expected (12, 4) r=2", circle.CX, circle.CY, circle.R)
+	}
+	commands, err := geom.ParseD(got.Groups[0].Paths[0].D)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []float64{12, 2, 14, 2}
+	for i, arg := range append(commands[0].Args, commands[1].Args...) {
+		if math.Abs(arg-want[i]) > 1e-6 {
+			t.Errorf("path args are %v %v expected %v", commands[0].Args, commands[1].Args, want)
+			break
+		}
+	}
+	// the original layer is left untouched
+	if layer.Ellipses[0].CX != 1 || layer.Groups[0].Paths[0].D != "M 1 1 L 2 1" {
+		t.Error("ResolveTransforms must not modify its receiver")
+	}
+}
+
+func TestResolveTransformsError(t *testing.T) {
+	layer := Group{ID: "layer1", Paths: []Path{{ID: "path1", D: "M 0 0", TransformList: "spin(3)"}}}
+	_, err := layer.ResolveTransforms(geom.Identity())
+	var elementErr *ElementError
+	if !errors.As(err, &elementErr) || elementErr.ID != "path1" {
+		t.Errorf("expected an error on path1, got %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+// Code generated by mixer gen-types from parts.svg. DO NOT EDIT.
+
+export type BodypartType = "eye" | "mouth" | "arm1" | "arm2" | "leg1" | "leg2" | "leg3" | "tail" | "ears" | "horns" | "antenna"
+
+export type Side = "left" | "right"
+
+export type PaletteSlot = "stroke" | "fill1" | "fill2" | "accent" | "highlight"
+
+export type BodyName = "ball" | "mush"
+
+export type PartName = "roundeye" | "stick"
+
+export type Body = {
+    path: string,
+    paths: SubPath[],
+    points: Point[],
+    frame: number,
+    name: string,
+    boundingBox: Rect
+}
+
+export type SubPath = {
+    id?: string,
+    path: string,
+    fill?: PaletteSlot,
+    stroke?: PaletteSlot,
+    strokeWidth: number,
+    opacity: number,
+    fillRule: string
+}
+
+export type Point = {
+    x: number,
+    y: number,
+    t: number,
+    type: BodypartType,
+    side?: Side,
+    index: number,
+    mirror?: boolean
+}
+
+export type Rect = {
+    topLeft: GeomPoint,
+    bottomRight: GeomPoint
+}
+
+export type GeomPoint = {
+    x: number,
+    y: number
+}
+
+export type BodyPart = {
+    path: string,
+    paths: SubPath[],
+    type: BodypartType,
+    frame: number,
+    name: string,
+    boundingBox: Rect
+}
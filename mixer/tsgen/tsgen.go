@@ -0,0 +1,207 @@
+// Package tsgen writes TypeScript declarations of Go types, as encoding/json
+// writes them, so that the renderer doesn't mirror the exported shapes by
+// hand.
+package tsgen
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"tama/schema"
+)
+
+var (
+	ErrNameTaken    = errors.New("type name already taken")
+	ErrUnknownField = errors.New("unknown field")
+)
+
+// Generator collects declarations, in the order they are added
+type Generator struct {
+	names     map[reflect.Type]string
+	overrides map[reflect.Type]map[string]string
+	decls     []decl
+}
+
+type decl struct {
+	name, body string
+}
+
+func New() *Generator {
+	return &Generator{names: make(map[reflect.Type]string), overrides: make(map[reflect.Type]map[string]string)}
+}
+
+// Override types the field of the struct t with ts, a declared name such as
+// a Union, instead of the type of its Go field. It must be called before t
+// is added.
+func (g *Generator) Override(t reflect.Type, field, ts string) error {
+	if t.Kind() != reflect.Struct {
+		return fmt.Errorf("%s is not a struct", t)
+	}
+	if _, ok := t.FieldByName(field); !ok {
+		return fmt.Errorf("%w: %s.%s", ErrUnknownField, t.Name(), field)
+	}
+	if g.overrides[t] == nil {
+		g.overrides[t] = make(map[string]string)
+	}
+	g.overrides[t][field] = ts
+	return nil
+}
+
+// Union declares a type as the union of string literals, never when there
+// are none
+func (g *Generator) Union(name string, values []string) error {
+	if g.taken(name) {
+		return fmt.Errorf("%w: %s", ErrNameTaken, name)
+	}
+	g.decls = append(g.decls, decl{name: name, body: union(values)})
+	return nil
+}
+
+// Enum declares the named string type t as the union of its values, fields
+// of type t are then typed with it instead of string
+func (g *Generator) Enum(t reflect.Type, values []string) error {
+	if t.Kind() != reflect.String {
+		return fmt.Errorf("%s is not a string", t)
+	}
+	name, err := g.name(t)
+	if err != nil {
+		return err
+	}
+	g.decls = append(g.decls, decl{name: name, body: union(values)})
+	return nil
+}
+
+// Add declares the struct t, and the structs its fields are made of. Structs
+// are named after their Go type, prefixed with their package name when
+// another package took it first.
+func (g *Generator) Add(t reflect.Type) error {
+	if _, ok := g.names[t]; ok {
+		return nil
+	}
+	if t.Kind() != reflect.Struct {
+		return fmt.Errorf("%s is not a struct", t)
+	}
+	name, err := g.name(t)
+	if err != nil {
+		return err
+	}
+	// nested structs come after, the declaration keeps its place
+	i := len(g.decls)
+	g.decls = append(g.decls, decl{name: name})
+	lines := make([]string, 0)
+	for _, field := range schema.Fields(t) {
+		ts, ok := g.overrides[t][field.GoName]
+		if !ok {
+			ts, err = g.typeOf(field.Type)
+			if err != nil {
+				return fmt.Errorf("%s.%s: %w", t.Name(), field.GoName, err)
+			}
+		}
+		key := field.Name
+		if !identReg.MatchString(key) {
+			key = strconv.Quote(key)
+		}
+		if field.Optional {
+			key += "?"
+		}
+		lines = append(lines, "    "+key+": "+ts)
+	}
+	g.decls[i].body = "{\n" + strings.Join(lines, ",\n") + "\n}"
+	return nil
+}
+
+// WriteTo writes every declaration as an exported type
+func (g *Generator) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+	for i, d := range g.decls {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("export type " + d.name + " = " + d.body + "\n")
+	}
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+var identReg = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+func (g *Generator) typeOf(t reflect.Type) (string, error) {
+	if name, ok := g.names[t]; ok {
+		return name, nil
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return g.typeOf(t.Elem())
+	case reflect.String:
+		return "string", nil
+	case reflect.Bool:
+		return "boolean", nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number", nil
+	case reflect.Interface:
+		return "unknown", nil
+	case reflect.Slice, reflect.Array:
+		elem, err := g.typeOf(t.Elem())
+		if err != nil {
+			return "", err
+		}
+		return elem + "[]", nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return "", fmt.Errorf("map key %s is not a string", t.Key())
+		}
+		elem, err := g.typeOf(t.Elem())
+		if err != nil {
+			return "", err
+		}
+		return "Record<string, " + elem + ">", nil
+	case reflect.Struct:
+		if err := g.Add(t); err != nil {
+			return "", err
+		}
+		return g.names[t], nil
+	}
+	return "", fmt.Errorf("no TypeScript type for %s", t)
+}
+
+// name picks the name of a Go type, unique among the declarations
+func (g *Generator) name(t reflect.Type) (string, error) {
+	name := t.Name()
+	if g.taken(name) {
+		pkg := path.Base(t.PkgPath())
+		name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+	}
+	if g.taken(name) {
+		return "", fmt.Errorf("%w: %s", ErrNameTaken, name)
+	}
+	g.names[t] = name
+	return name, nil
+}
+
+func (g *Generator) taken(name string) bool {
+	for _, d := range g.decls {
+		if d.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func union(values []string) string {
+	if len(values) == 0 {
+		return "never"
+	}
+	quoted := make([]string, 0, len(values))
+	for _, v := range values {
+		quoted = append(quoted, strconv.Quote(v))
+	}
+	return strings.Join(quoted, " | ")
+}
@@ -0,0 +1,85 @@
+package tsgen
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"tama/geom"
+)
+
+type kind string
+
+type Point struct {
+	X    float64 `json:"x"`
+	Kind kind    `json:"kind,omitempty"`
+}
+
+type shape struct {
+	Name   string            `json:"name"`
+	Points []Point           `json:"points"`
+	Box    geom.Rect         `json:"box"`
+	Tags   map[string]int    `json:"tags,omitempty"`
+	Extra  any               `json:"extra-data,omitempty"`
+	Refs   map[string]*Point `json:"refs"`
+	Hidden bool              `json:"-"`
+}
+
+func TestGenerator(t *testing.T) {
+	g := New()
+	if err := g.Enum(reflect.TypeFor[kind](), []string{"dot", "cross"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Union("Empty", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Override(reflect.TypeFor[shape](), "Extra", "Empty"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Add(reflect.TypeFor[shape]()); err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if _, err := g.WriteTo(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := `export type kind = "dot" | "cross"
+
+export type Empty = never
+
+export type shape = {
+    name: string,
+    points: Point[],
+    box: Rect,
+    tags?: Record<string, number>,
+    "extra-data"?: Empty,
+    refs: Record<string, Point>
+}
+
+export type Point = {
+    x: number,
+    kind?: kind
+}
+
+export type Rect = {
+    topLeft: GeomPoint,
+    bottomRight: GeomPoint
+}
+
+export type GeomPoint = {
+    x: number,
+    y: number
+}
+`
+	if b.String() != want {
+		t.Errorf("got\n%s\nexpected\n%s", b.String(), want)
+	}
+
+	if err := g.Union("Point", []string{"a"}); !errors.Is(err, ErrNameTaken) {
+		t.Errorf("got %v expected %v", err, ErrNameTaken)
+	}
+	if err := g.Override(reflect.TypeFor[shape](), "Size", "number"); !errors.Is(err, ErrUnknownField) {
+		t.Errorf("got %v expected %v", err, ErrUnknownField)
+	}
+}
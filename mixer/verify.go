@@ -0,0 +1,48 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"tama/export"
+)
+
+// runVerify checks output directories against the schema of the files the
+// mixer writes, every problem is printed with its file
+func runVerify(args []string, stdout, stderr io.Writer) int {
+	var verbose bool
+	fs := flag.NewFlagSet("mixer verify", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: mixer verify [flags] [dir ...]\n\n")
+		fmt.Fprintf(stderr, "Validates the files of output directories (default out) against the schema of format version %d.\n\n", export.FormatVersion)
+		fs.PrintDefaults()
+	}
+	fs.BoolVar(&verbose, "v", false, "print what is being done")
+	if err := fs.Parse(args); err == flag.ErrHelp {
+		return 0
+	} else if err != nil {
+		return 2
+	}
+	dirs := fs.Args()
+	if len(dirs) == 0 {
+		dirs = []string{"out"}
+	}
+
+	failed := false
+	for _, dir := range dirs {
+		errs := export.Verify(dir)
+		for _, err := range errs {
+			fmt.Fprintf(stderr, "mixer: %s: %v\n", dir, err)
+		}
+		if verbose {
+			fmt.Fprintf(stdout, "%s: %d problems\n", dir, len(errs))
+		}
+		failed = failed || len(errs) > 0
+	}
+	if failed {
+		return 1
+	}
+	return 0
+}
@@ -114,6 +114,9 @@ export class PetRenderer implements Renderer {
             const matrix = new DOMMatrix();
             matrix.translateSelf(anchor.x, anchor.y);
             matrix.rotateSelf(anchor.t);           // rotation en degrés
+            if (anchor.mirror) {
+                matrix.scaleSelf(-1, 1);           // miroir pour le côté droit
+            }
 
             // Ajout du sous-chemin avec la transformation
             const subpath = new Path2D(part.path);
@@ -121,6 +124,7 @@ export class PetRenderer implements Renderer {
 
             const radians = anchor.t * (Math.PI / 180)
             const bounds = [part.boundingBox.topLeft, { x: part.boundingBox.bottomRight.x, y: part.boundingBox.topLeft.y }, part.boundingBox.bottomRight, { x: part.boundingBox.topLeft.x, y: part.boundingBox.bottomRight.y }]
+                .map((point) => anchor.mirror ? { ...point, x: -point.x } : point)
                 .map((point) => ({ ...point, x: point.x * Math.cos(radians) - point.y * Math.sin(radians), y: point.x * Math.sin(radians) + point.y * Math.cos(radians) } as Point))
                 .map((point) => ({ ...point, x: point.x + anchor.x, y: point.y + anchor.y } as Point)) // translate 
                 .reduce(
@@ -169,10 +173,10 @@ export class PetRenderer implements Renderer {
         this.outPath = outParts
 
 
-        let minX = 0;
-        let minY = 0;
-        let maxX = this.body.size.x;
-        let maxY = this.body.size.y;
+        let minX = this.body.boundingBox.topLeft.x;
+        let minY = this.body.boundingBox.topLeft.y;
+        let maxX = this.body.boundingBox.bottomRight.x;
+        let maxY = this.body.boundingBox.bottomRight.y;
 
         boxes.forEach(b => {
             minX = Math.min(minX, b.x);
@@ -30,7 +30,10 @@ export type Point = {
     x: number, 
     y: number,
     t: number,
-    type?: string
+    type?: string,
+    side?: "left" | "right",
+    index?: number,
+    mirror?: boolean
 }
 
 export type Rect = {
@@ -40,16 +43,33 @@ export type Rect = {
     height: number,
 }
 
+export type PaletteSlot = "stroke" | "fill1" | "fill2" | "accent" | "highlight"
+
+export type SubPath = {
+    id?: string,
+    path: string,
+    fill?: PaletteSlot,
+    stroke?: PaletteSlot,
+    strokeWidth: number,
+    opacity: number,
+    fillRule: CanvasFillRule
+}
+
 export type BodyFrame = {
     path: string,
+    paths: SubPath[],
     points: Point[],
     frame: number,
     name: string,
-    size: Point
+    boundingBox: {
+        topLeft: Point,
+        bottomRight: Point
+    }
 }
 
 export type PartFrame = {
     path: string,
+    paths: SubPath[],
     type: string,
     frame: number,
     name: string,
@@ -59,6 +79,39 @@ export type PartFrame = {
     }
 }
 
+export type FramesFile<T> = {
+    version: number,
+    frames: T[]
+}
+
+export type TypeInfo = {
+    type: string,
+    inside: boolean,
+    normalize: boolean,
+    pair?: string,
+    mirror?: boolean
+}
+
+export type AssetManifest = {
+    version: number,
+    types: TypeInfo[],
+    bodies: {
+        name: string,
+        file: string,
+        frames: number[],
+        anchors: string[]
+    }[],
+    parts: {
+        id: string,
+        type: string,
+        name: string,
+        file: string,
+        frames: number[],
+        pair?: string
+    }[],
+    palette?: string
+}
+
 export interface IStateMachine {
     Enter(nextState: State): boolean
     State(): State
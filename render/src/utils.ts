@@ -1,4 +1,4 @@
-import { BodyFrame, PartFrame } from "./types"
+import { BodyFrame, FramesFile, PartFrame } from "./types"
 
 export const COLOR_PALETTE = {
     pet: {
@@ -23,14 +23,14 @@ export const COLOR_PALETTE = {
 let BODIES: BodyFrame[][] = []
 export async function LoadBodies() {
     // @ts-expect-error no typedef
-    const promises = Object.values(import.meta.glob('./../../mixer/out/bodies/*.json')).map((m: any) => m().then((m: any) => m.default))
+    const promises = Object.values(import.meta.glob('./../../mixer/out/bodies/*.json')).map((m: any) => m().then((m: any) => (m.default as FramesFile<BodyFrame>).frames))
     BODIES = await Promise.all(promises)
 }
 
 let BODYPARTS: PartFrame[][] = []
 export async function LoadBodyparts() {
     // @ts-expect-error no typedef
-    const promises = Object.values(import.meta.glob('./../../mixer/out/bodyparts/*.json')).map((m: any) => m().then((m: any) => m.default))
+    const promises = Object.values(import.meta.glob('./../../mixer/out/bodyparts/*.json')).map((m: any) => m().then((m: any) => (m.default as FramesFile<PartFrame>).frames))
     BODYPARTS = (await Promise.all(promises)).map((a: PartFrame[]) => a.sort((a, b) => a.frame - b.frame))
 }
 